@@ -0,0 +1,228 @@
+package geohash
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"sync"
+)
+
+// aggregatorShards is the number of independently locked maps of an Aggregator.
+// Concurrent adds to different cells rarely contend for the same lock.
+const aggregatorShards = 64
+
+// Aggregator counts points per geohash cell at a base bit precision, such as for density heatmaps.
+// Each cell holds the number and total weight of its points, and statistics of an optional value recorded with each point.
+// Cells can be rolled up to any coarser bit precision, as the geohash integer of a parent cell is its child shifted right.
+// An Aggregator is safe for concurrent use. Cells are spread over sharded maps, each with its own lock.
+type Aggregator struct {
+	bits   int
+	shards [aggregatorShards]aggregatorShard
+}
+
+// aggregatorShard is a locked map of cells. The padding keeps locks of neighboring shards on separate cache lines.
+type aggregatorShard struct {
+	mu    sync.Mutex
+	cells map[uint64]*CellStats
+	_     [48]byte
+}
+
+// CellStats are the statistics of the points in a cell.
+// Count and Weight include every point, while Sum, Min and Max only include points added with a value.
+type CellStats struct {
+	Count  int64   `json:"count"`
+	Weight float64 `json:"weight"`
+	Values int64   `json:"values"`
+	Sum    float64 `json:"sum"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Mean returns the mean of the values added to the cell, or 0 if no values were added.
+func (s CellStats) Mean() float64 {
+	if s.Values == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Values)
+}
+
+// merge adds the statistics of o to s.
+func (s *CellStats) merge(o CellStats) {
+	if o.Values > 0 {
+		if s.Values == 0 {
+			s.Min, s.Max = o.Min, o.Max
+		}
+		s.Min = math.Min(s.Min, o.Min)
+		s.Max = math.Max(s.Max, o.Max)
+	}
+	s.Count += o.Count
+	s.Weight += o.Weight
+	s.Values += o.Values
+	s.Sum += o.Sum
+}
+
+// AggregateCell is a cell of an Aggregator at a bit precision, identified by its geohash integer in the layout of EncodeIntPrecision.
+type AggregateCell struct {
+	Hash uint64
+	Bits int
+	CellStats
+}
+
+// Box returns the bounding box of the cell.
+func (c AggregateCell) Box() Box {
+	return WGS84.DecodeIntBox(c.Hash, c.Bits)
+}
+
+// NewAggregator returns an Aggregator counting points in cells of the provided bit precision.
+// Acceptable bit values are 1 to 64.
+func NewAggregator(bits int) *Aggregator {
+	a := &Aggregator{bits: validate(bitsMin, bitsMax, bits)}
+	for i := range a.shards {
+		a.shards[i].cells = map[uint64]*CellStats{}
+	}
+	return a
+}
+
+// Bits returns the base bit precision of the Aggregator.
+func (a *Aggregator) Bits() int {
+	return a.bits
+}
+
+// Add counts a point with a weight of 1.
+func (a *Aggregator) Add(lat, lng float64) {
+	a.add(lat, lng, CellStats{Count: 1, Weight: 1})
+}
+
+// AddWeighted counts a point with the provided weight, such as the number of orders at a location.
+func (a *Aggregator) AddWeighted(lat, lng, weight float64) {
+	a.add(lat, lng, CellStats{Count: 1, Weight: weight})
+}
+
+// AddValue counts a point with a weight of 1 and records a value for the sum, min, max and mean of its cell.
+func (a *Aggregator) AddValue(lat, lng, value float64) {
+	a.add(lat, lng, CellStats{Count: 1, Weight: 1, Values: 1, Sum: value, Min: value, Max: value})
+}
+
+// add merges s into the cell containing lat, lng.
+func (a *Aggregator) add(lat, lng float64, s CellStats) {
+	hash := encodeIntClamp(lat, lng, a.bits)
+
+	// Nearby cells share their high bits, so the shard is chosen by a multiplicative hash of the whole integer.
+	shard := &a.shards[(hash*0x9e3779b97f4a7c15)>>58]
+	shard.mu.Lock()
+	if c, ok := shard.cells[hash]; ok {
+		c.merge(s)
+	} else {
+		shard.cells[hash] = &s
+	}
+	shard.mu.Unlock()
+}
+
+// Len returns the number of cells with points at the base precision.
+func (a *Aggregator) Len() int {
+	n := 0
+	for i := range a.shards {
+		a.shards[i].mu.Lock()
+		n += len(a.shards[i].cells)
+		a.shards[i].mu.Unlock()
+	}
+	return n
+}
+
+// Cells returns the cells with points rolled up to the provided bit precision, ordered by geohash integer.
+// Bits are clamped to 1 and the base precision of the Aggregator.
+// Each shard is locked in turn, so points added concurrently may be partially included.
+func (a *Aggregator) Cells(bits int) []AggregateCell {
+	bits = validate(bitsMin, a.bits, bits)
+	shift := a.bits - bits
+
+	cells := map[uint64]*CellStats{}
+	for i := range a.shards {
+		a.shards[i].mu.Lock()
+		for hash, s := range a.shards[i].cells {
+			if c, ok := cells[hash>>shift]; ok {
+				c.merge(*s)
+			} else {
+				c := *s
+				cells[hash>>shift] = &c
+			}
+		}
+		a.shards[i].mu.Unlock()
+	}
+
+	res := make([]AggregateCell, 0, len(cells))
+	for hash, s := range cells {
+		res = append(res, AggregateCell{Hash: hash, Bits: bits, CellStats: *s})
+	}
+	slices.SortFunc(res, func(x, y AggregateCell) int {
+		return cmp.Compare(x.Hash, y.Hash)
+	})
+	return res
+}
+
+// Top returns the n cells with the greatest weight rolled up to the provided bit precision, heaviest first.
+// Cells of equal weight are ordered by geohash integer.
+func (a *Aggregator) Top(n, bits int) []AggregateCell {
+	cells := a.Cells(bits)
+	slices.SortStableFunc(cells, func(x, y AggregateCell) int {
+		return cmp.Compare(y.Weight, x.Weight)
+	})
+	return cells[:min(max(n, 0), len(cells))]
+}
+
+// aggregateGeoJSON is a GeoJSON feature collection of cell polygons.
+type aggregateGeoJSON struct {
+	Type     string             `json:"type"`
+	Features []aggregateFeature `json:"features"`
+}
+
+type aggregateFeature struct {
+	Type       string            `json:"type"`
+	Geometry   aggregateGeometry `json:"geometry"`
+	Properties aggregateProps    `json:"properties"`
+}
+
+type aggregateGeometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+type aggregateProps struct {
+	Geohash string `json:"geohash,omitempty"`
+	Hash    uint64 `json:"hash"`
+	Bits    int    `json:"bits"`
+	CellStats
+	Mean float64 `json:"mean"`
+}
+
+// WriteGeoJSON writes the cells rolled up to the provided bit precision to w as a GeoJSON FeatureCollection.
+// Each cell is a Polygon feature whose properties are its statistics, its geohash integer and bits,
+// and its geohash string when bits is a multiple of 5. Coordinates are lng, lat as required by RFC 7946.
+func (a *Aggregator) WriteGeoJSON(w io.Writer, bits int) error {
+	cells := a.Cells(bits)
+	fc := aggregateGeoJSON{Type: "FeatureCollection", Features: make([]aggregateFeature, len(cells))}
+
+	for i, c := range cells {
+		b := c.Box()
+		props := aggregateProps{Hash: c.Hash, Bits: c.Bits, CellStats: c.CellStats, Mean: c.Mean()}
+		if c.Bits%5 == 0 {
+			props.Geohash = EncodeIntToStr(c.Hash, c.Bits/5)
+		}
+
+		// The exterior ring is counterclockwise and closed.
+		fc.Features[i] = aggregateFeature{
+			Type: "Feature",
+			Geometry: aggregateGeometry{
+				Type: "Polygon",
+				Coordinates: [][][2]float64{{
+					{b.MinLng, b.MinLat}, {b.MaxLng, b.MinLat}, {b.MaxLng, b.MaxLat}, {b.MinLng, b.MaxLat}, {b.MinLng, b.MinLat},
+				}},
+			},
+			Properties: props,
+		}
+	}
+
+	return json.NewEncoder(w).Encode(fc)
+}
@@ -0,0 +1,173 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator(40)
+	coarse := NewAggregator(20)
+	points := testPoints(10000, 1)
+
+	// Concurrent adds from many goroutines are all counted.
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(points); i += 8 {
+				a.AddWeighted(points[i].Lat, points[i].Lng, 2)
+			}
+		}(g)
+	}
+	wg.Wait()
+	for _, p := range points {
+		coarse.AddWeighted(p.Lat, p.Lng, 2)
+	}
+
+	var count int64
+	for _, c := range a.Cells(40) {
+		count += c.Count
+	}
+	if count != int64(len(points)) {
+		t.Errorf("Cells count = %d, want %d", count, len(points))
+	}
+	if a.Len() != len(a.Cells(64)) {
+		t.Errorf("Len = %d, want %d", a.Len(), len(a.Cells(64)))
+	}
+
+	// Rolling up matches aggregating at the coarser precision directly.
+	rolled, direct := a.Cells(20), coarse.Cells(20)
+	if len(rolled) != len(direct) {
+		t.Fatalf("Cells(20) = %d cells, want %d", len(rolled), len(direct))
+	}
+	for i := range rolled {
+		if rolled[i] != direct[i] {
+			t.Errorf("Cells(20) = %+v, want %+v", rolled[i], direct[i])
+		}
+	}
+
+	top := a.Top(3, 20)
+	if len(top) != 3 {
+		t.Fatalf("Top = %d cells, want 3", len(top))
+	}
+	for _, c := range a.Cells(20) {
+		if c.Weight > top[2].Weight && c != top[0] && c != top[1] {
+			t.Errorf("Top = %+v, missing %+v", top, c)
+		}
+	}
+	if top[0].Weight < top[1].Weight || top[1].Weight < top[2].Weight {
+		t.Errorf("Top = %+v, want heaviest first", top)
+	}
+	if n := len(a.Top(-1, 10)); n != 0 {
+		t.Errorf("Top(-1) = %d cells, want 0", n)
+	}
+}
+
+func TestAggregatorMaxEdge(t *testing.T) {
+	a := NewAggregator(20)
+	a.Add(90, 180)
+	a.Add(89.99, 179.99)
+
+	res := a.Cells(20)
+	if len(res) != 1 || res[0].Hash != EncodeStrToInt("zzzz") || res[0].Count != 2 {
+		t.Errorf("Cells = %+v, want zzzz with a count of 2", res)
+	}
+}
+
+func TestAggregatorValues(t *testing.T) {
+	a := NewAggregator(25)
+	a.Add(57.64911, 10.40744)
+	a.AddValue(57.64911, 10.40744, 4)
+	a.AddValue(57.64912, 10.40745, -2)
+	a.AddWeighted(57.64911, 10.40744, 0.5)
+	a.AddValue(-33.8688, 151.2093, 7)
+
+	cells := a.Cells(25)
+	if len(cells) != 2 {
+		t.Fatalf("Cells = %d cells, want 2", len(cells))
+	}
+
+	want := CellStats{Count: 4, Weight: 3.5, Values: 2, Sum: 2, Min: -2, Max: 4}
+	c := cells[1]
+	if EncodeIntToStr(c.Hash, 5) != "u4pru" || c.CellStats != want {
+		t.Errorf("Cells = %s %+v, want u4pru %+v", EncodeIntToStr(c.Hash, 5), c.CellStats, want)
+	}
+	if c.Mean() != 1 {
+		t.Errorf("Mean = %v, want 1", c.Mean())
+	}
+	if !c.Box().Contains(57.64911, 10.40744) {
+		t.Errorf("Box = %+v, want to contain 57.64911, 10.40744", c.Box())
+	}
+	if (CellStats{Count: 1}).Mean() != 0 {
+		t.Errorf("Mean = %v, want 0", (CellStats{Count: 1}).Mean())
+	}
+
+	// Bits beyond the base precision are clamped.
+	if got := a.Cells(40); len(got) != 2 || got[0].Bits != 25 {
+		t.Errorf("Cells(40) = %+v, want 2 cells of 25 bits", got)
+	}
+}
+
+func TestAggregatorGeoJSON(t *testing.T) {
+	a := NewAggregator(30)
+	a.AddValue(57.64911, 10.40744, 4)
+	a.AddValue(57.64911, 10.40744, 2)
+
+	var buf bytes.Buffer
+	if err := a.WriteGeoJSON(&buf, 25); err != nil {
+		t.Fatalf("WriteGeoJSON = %s", err.Error())
+	}
+
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates [][][2]float64
+			}
+			Properties map[string]any
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &fc); err != nil {
+		t.Fatalf("WriteGeoJSON = %s, invalid JSON %s", buf.String(), err.Error())
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Fatalf("WriteGeoJSON = %s, want 1 feature", buf.String())
+	}
+
+	f := fc.Features[0]
+	box := DecodeBox("u4pru")
+	ring := f.Geometry.Coordinates[0]
+	if f.Geometry.Type != "Polygon" || len(ring) != 5 || ring[0] != ring[4] || ring[0] != [2]float64{box.MinLng, box.MinLat} || ring[2] != [2]float64{box.MaxLng, box.MaxLat} {
+		t.Errorf("WriteGeoJSON geometry = %+v, want box of u4pru", f.Geometry)
+	}
+	if f.Properties["geohash"] != "u4pru" || f.Properties["count"] != 2.0 || f.Properties["mean"] != 3.0 {
+		t.Errorf("WriteGeoJSON properties = %v, want u4pru with count 2 and mean 3", f.Properties)
+	}
+}
+
+// testPoints returns n random points within a degree of New York.
+func testPoints(n int, seed int64) []Point {
+	r := rand.New(rand.NewSource(seed))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{Lat: 40.7 + r.Float64()*2 - 1, Lng: -74 + r.Float64()*2 - 1}
+	}
+	return points
+}
+
+func BenchmarkAggregatorAdd(b *testing.B) {
+	a := NewAggregator(40)
+	points := testPoints(1024, 1)
+	b.RunParallel(func(pb *testing.PB) {
+		for n := 0; pb.Next(); n++ {
+			p := points[n%len(points)]
+			a.Add(p.Lat, p.Lng)
+		}
+	})
+}
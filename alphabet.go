@@ -0,0 +1,207 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	geohash36       = "23456789bBCdDFgGhHjJKlLMnNPqQrRtTVWX"
+	geohash36Matrix = 6
+)
+
+// ErrInvalidAlphabet is returned by NewAlphabet for alphabets that are not 32 or 36 unique ASCII characters.
+var ErrInvalidAlphabet = errors.New("geohash: invalid alphabet")
+
+var (
+	// Base32Alphabet is the standard geohash alphabet.
+	Base32Alphabet = mustAlphabet(base32)
+
+	// Geohash36Alphabet is the case sensitive alphabet of geohash-36, which subdivides each cell into a 6x6 grid.
+	// Reference: https://en.wikipedia.org/wiki/Geohash-36
+	Geohash36Alphabet = mustAlphabet(geohash36)
+)
+
+// Alphabet is a validated set of characters with a reverse lookup table used to encode and decode geohash strings.
+// The length of the alphabet determines how each character subdivides a cell.
+// A 32 character alphabet uses the standard geohash bit interleave, where each character holds 5 alternating lng, lat bits.
+// This allows interoperating with systems that represent standard geohashes with a different set of characters.
+// A 36 character alphabet uses the geohash-36 6x6 grid, where the characters are laid out in rows from northwest to southeast.
+// Geohash-36 is not a bit interleave, so geohash-36 strings cannot be converted to geohash integers.
+type Alphabet struct {
+	chars  string
+	lookup [256]int8
+}
+
+// NewAlphabet returns an Alphabet of 32 or 36 unique ASCII characters.
+// ErrInvalidAlphabet is returned for any other length, non-ASCII characters, or repeated characters.
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) != len(base32) && len(chars) != geohash36Matrix*geohash36Matrix {
+		return nil, fmt.Errorf("%w: %d characters, want 32 or 36", ErrInvalidAlphabet, len(chars))
+	}
+
+	a := &Alphabet{chars: chars}
+	for i := range a.lookup {
+		a.lookup[i] = -1
+	}
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("%w: non-ASCII character at index %d", ErrInvalidAlphabet, i)
+		}
+		if a.lookup[c] >= 0 {
+			return nil, fmt.Errorf("%w: %q repeated at index %d", ErrInvalidAlphabet, c, i)
+		}
+		a.lookup[c] = int8(i)
+	}
+
+	return a, nil
+}
+
+// mustAlphabet returns the Alphabet of chars, panicking if it is invalid. It is used for package level alphabets.
+func mustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String returns the characters of the alphabet in index order.
+func (a *Alphabet) String() string {
+	return a.chars
+}
+
+// Len returns the number of characters in the alphabet.
+func (a *Alphabet) Len() int {
+	return len(a.chars)
+}
+
+// Index returns the index of c in the alphabet, or -1 if c is not in the alphabet.
+func (a *Alphabet) Index(c byte) int {
+	return int(a.lookup[c])
+}
+
+// Validate returns ErrInvalidHash if hash contains a character that is not in the alphabet.
+func (a *Alphabet) Validate(hash string) error {
+	for i := 0; i < len(hash); i++ {
+		if a.lookup[hash[i]] < 0 {
+			return fmt.Errorf("%w: %q at index %d", ErrInvalidHash, hash[i], i)
+		}
+	}
+	return nil
+}
+
+// Encode returns a geohash string of the lat, lng coordinates using the alphabet.
+// Acceptable precision values are 1 to 20 characters.
+func (a *Alphabet) Encode(lat, lng float64, precision int) string {
+	precision = validate(precisionMin, precisionHigh, precision)
+
+	if len(a.chars) != len(base32) {
+		return a.encode36(lat, lng, precision)
+	}
+
+	var hash string
+	if precision <= precisionMax {
+		hash = encode(lat, lng, precision)
+	} else {
+		hash = encodeBitwiseOr(lat, lng, precision)
+	}
+	return translate(hash, Base32Alphabet, a)
+}
+
+// Decode returns the estimated lat, lng coordinates of a geohash string encoded using the alphabet.
+// Exceeding character limit will truncate the geohash string to the precision max of 20 characters.
+// ErrInvalidHash is returned if hash contains a character that is not in the alphabet.
+func (a *Alphabet) Decode(hash string) (float64, float64, error) {
+	box, err := a.DecodeBox(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, lng := box.Center()
+	return lat, lng, nil
+}
+
+// DecodeBox returns the bounding box of the cell described by a geohash string encoded using the alphabet.
+// Exceeding character limit will truncate the geohash string to the precision max of 20 characters.
+// ErrInvalidHash is returned if hash contains a character that is not in the alphabet.
+func (a *Alphabet) DecodeBox(hash string) (Box, error) {
+	hash = truncateHigh(hash)
+	if err := a.Validate(hash); err != nil {
+		return Box{}, err
+	}
+
+	if len(a.chars) != len(base32) {
+		return a.decodeBox36(hash), nil
+	}
+	return decodeBox(translate(hash, a, Base32Alphabet)), nil
+}
+
+// Translate converts a geohash string encoded using the alphabet to the same cell encoded using another alphabet of the same length.
+// ErrInvalidHash is returned if hash contains a character that is not in the alphabet.
+// ErrInvalidAlphabet is returned if the alphabets are of different lengths, as their cells do not correspond.
+func (a *Alphabet) Translate(hash string, to *Alphabet) (string, error) {
+	if len(a.chars) != len(to.chars) {
+		return "", fmt.Errorf("%w: cannot translate %d to %d characters", ErrInvalidAlphabet, len(a.chars), len(to.chars))
+	}
+	if err := a.Validate(hash); err != nil {
+		return "", err
+	}
+	return translate(hash, a, to), nil
+}
+
+// translate maps every character of a validated hash from one alphabet to the character with the same index in another.
+func translate(hash string, from, to *Alphabet) string {
+	b := []byte(hash)
+	for i := range b {
+		b[i] = to.chars[from.lookup[b[i]]]
+	}
+	return string(b)
+}
+
+// encode36 returns a geohash-36 string of the lat, lng coordinates.
+// On each iteration, the cell is divided into 6 columns (west to east) and 6 rows (north to south).
+// The character index is the row times 6 plus the column containing the coordinates.
+// The cell is then narrowed to the selected row and column for the next character.
+func (a *Alphabet) encode36(lat, lng float64, precision int) string {
+	latmin, latmax := -latMax, latMax
+	lngmin, lngmax := -lngMax, lngMax
+
+	hash := make([]byte, precision)
+	for i := range hash {
+		h := (latmax - latmin) / geohash36Matrix
+		w := (lngmax - lngmin) / geohash36Matrix
+
+		row := validate(0, geohash36Matrix-1, int((latmax-lat)/h))
+		col := validate(0, geohash36Matrix-1, int((lng-lngmin)/w))
+		hash[i] = a.chars[row*geohash36Matrix+col]
+
+		latmax -= float64(row) * h
+		latmin = latmax - h
+		lngmin += float64(col) * w
+		lngmax = lngmin + w
+	}
+
+	return string(hash)
+}
+
+// decodeBox36 returns the bounding box of a validated geohash-36 string by narrowing the cell as encode36 does.
+func (a *Alphabet) decodeBox36(hash string) Box {
+	latmin, latmax := -latMax, latMax
+	lngmin, lngmax := -lngMax, lngMax
+
+	for i := 0; i < len(hash); i++ {
+		idx := int(a.lookup[hash[i]])
+		row, col := idx/geohash36Matrix, idx%geohash36Matrix
+
+		h := (latmax - latmin) / geohash36Matrix
+		w := (lngmax - lngmin) / geohash36Matrix
+
+		latmax -= float64(row) * h
+		latmin = latmax - h
+		lngmin += float64(col) * w
+		lngmax = lngmin + w
+	}
+
+	return Box{MinLat: latmin, MaxLat: latmax, MinLng: lngmin, MaxLng: lngmax}
+}
@@ -0,0 +1,104 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAlphabetBase32(t *testing.T) {
+	for _, c := range testCases {
+		if res := Base32Alphabet.Encode(c.lat, c.lng, testPrecision); res != c.hash {
+			t.Errorf("Encode = %s, want %s", res, c.hash)
+		}
+		if res := Base32Alphabet.Encode(c.lat, c.lng, testPrecisionHigh); res != c.hashHighPrec {
+			t.Errorf("Encode = %s, want %s", res, c.hashHighPrec)
+		}
+
+		lat, lng, err := Base32Alphabet.Decode(c.hashHighPrec)
+		wantLat, wantLng := DecodeHighPrecision(c.hashHighPrec)
+		if err != nil || lat != wantLat || lng != wantLng {
+			t.Errorf("Decode = %.9f, %.9f, %v, want %.9f, %.9f", lat, lng, err, wantLat, wantLng)
+		}
+	}
+}
+
+func TestAlphabetCustom(t *testing.T) {
+	// A legacy alphabet using uppercase characters in a different order.
+	legacy, err := NewAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567")
+	if err != nil {
+		t.Fatalf("NewAlphabet = %s", err.Error())
+	}
+
+	for _, c := range testCases {
+		res := legacy.Encode(c.lat, c.lng, testPrecision)
+
+		std, err := legacy.Translate(res, Base32Alphabet)
+		if err != nil || std != c.hash {
+			t.Errorf("Translate = %s, %v, want %s", std, err, c.hash)
+		}
+
+		box, err := legacy.DecodeBox(res)
+		if err != nil || box != DecodeBox(c.hash) {
+			t.Errorf("DecodeBox = %+v, %v, want %+v", box, err, DecodeBox(c.hash))
+		}
+	}
+
+	if _, err := legacy.Translate("AB", Geohash36Alphabet); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Translate = %v, want %v", err, ErrInvalidAlphabet)
+	}
+	if _, _, err := legacy.Decode("ab"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Decode = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestAlphabetGeohash36(t *testing.T) {
+	// Example from https://en.wikipedia.org/wiki/Geohash-36 (London Bridge).
+	lat, lng := 51.504444, -0.086666
+	if res := Geohash36Alphabet.Encode(lat, lng, 10); res != "bdrdC26BqH" {
+		t.Errorf("Encode = %s, want bdrdC26BqH", res)
+	}
+
+	resLat, resLng, err := Geohash36Alphabet.Decode("bdrdC26BqH")
+	if err != nil || math.Abs(resLat-lat) > 0.00001 || math.Abs(resLng-lng) > 0.00001 {
+		t.Errorf("Decode = %.6f, %.6f, %v, want %.6f, %.6f", resLat, resLng, err, lat, lng)
+	}
+
+	// The first character divides the world in a 6x6 grid from the northwest corner.
+	corners := []struct {
+		lat, lng float64
+		hash     string
+	}{
+		{89, -179, "2"},
+		{89, 179, "7"},
+		{-89, -179, "R"},
+		{-89, 179, "X"},
+	}
+	for _, c := range corners {
+		if res := Geohash36Alphabet.Encode(c.lat, c.lng, 1); res != c.hash {
+			t.Errorf("Encode = %s, want %s", res, c.hash)
+		}
+	}
+
+	for _, c := range testCases {
+		box, err := Geohash36Alphabet.DecodeBox(Geohash36Alphabet.Encode(c.lat, c.lng, testPrecision))
+		if err != nil || !box.Contains(c.lat, c.lng) {
+			t.Errorf("DecodeBox = %+v, %v, does not contain %.9f, %.9f", box, err, c.lat, c.lng)
+		}
+	}
+}
+
+func TestNewAlphabet(t *testing.T) {
+	for _, s := range []string{"", base32[:31], base32[:31] + "0", strings.Repeat("a", 36), base32[:30] + "é"} {
+		if _, err := NewAlphabet(s); !errors.Is(err, ErrInvalidAlphabet) {
+			t.Errorf("NewAlphabet(%q) = %v, want %v", s, err, ErrInvalidAlphabet)
+		}
+	}
+}
+
+func BenchmarkAlphabetEncode(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		Geohash36Alphabet.Encode(testLat, testLng, testPrecision)
+	}
+}
@@ -0,0 +1,95 @@
+package geohash
+
+import (
+	"math"
+	"slices"
+)
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// Point is a pair of lat, lng coordinates in degrees.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Box is the bounding box of a geohash cell in degrees.
+// The southwest corner is MinLat, MinLng and the northeast corner is MaxLat, MaxLng.
+type Box struct {
+	MinLat float64 `json:"minLat"`
+	MaxLat float64 `json:"maxLat"`
+	MinLng float64 `json:"minLng"`
+	MaxLng float64 `json:"maxLng"`
+}
+
+// Center returns the lat, lng coordinates of the center of the box.
+// This is the estimated point returned when decoding a geohash string.
+func (b Box) Center() (float64, float64) {
+	lat := (b.MinLat + b.MaxLat) / 2
+	lng := (b.MinLng + b.MaxLng) / 2
+	return lat, lng
+}
+
+// Height returns the latitude span of the box in degrees.
+func (b Box) Height() float64 {
+	return b.MaxLat - b.MinLat
+}
+
+// Width returns the longitude span of the box in degrees.
+func (b Box) Width() float64 {
+	return b.MaxLng - b.MinLng
+}
+
+// Area returns the surface area of the box in square meters on a spherical Earth of mean radius.
+func (b Box) Area() float64 {
+	dLng := b.Width() * math.Pi / 180
+	return earthRadius * earthRadius * dLng * (math.Sin(b.MaxLat*math.Pi/180) - math.Sin(b.MinLat*math.Pi/180))
+}
+
+// Contains reports whether the lat, lng coordinates fall within the box.
+// Like encoding, the min edges are inclusive and the max edges are exclusive.
+func (b Box) Contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat < b.MaxLat && lng >= b.MinLng && lng < b.MaxLng
+}
+
+// PrecisionForSize returns the character precision of the geohash cells closest in size to a cell of height by width degrees.
+// Sizes are compared by area on a log scale, so a cell of half the area and a cell of twice the area are equally close.
+// This maps cells of other grid systems to the nearest equivalent geohash precision. Sizes without area return 12.
+func PrecisionForSize(height, width float64) int {
+	area := height * width
+	if !(area > 0) {
+		return precisionMax
+	}
+
+	best, bestDiff := precisionMax, math.Inf(1)
+	for p := precisionMin; p <= precisionMax; p++ {
+		latBits, lngBits := gridBits(p * 5)
+		cell := 2 * latMax / math.Exp2(float64(latBits)) * 2 * lngMax / math.Exp2(float64(lngBits))
+		if diff := math.Abs(math.Log(cell / area)); diff < bestDiff {
+			best, bestDiff = p, diff
+		}
+	}
+	return best
+}
+
+// CoverBox returns the geohash strings of every cell of the provided character precision that overlaps box.
+// Acceptable precision values are 1 to 12 characters.
+// Cells are ordered row by row starting in the southwest corner.
+// A box crossing the antimeridian (MinLng > MaxLng) is covered as two boxes split at the antimeridian, west first.
+// Use CellsInBox to stream the cells of large boxes rather than collecting them.
+func CoverBox(box Box, precision int) []string {
+	return slices.Collect(CellsInBox(box, precision))
+}
+
+// splitBox returns box as one box, or as two boxes if it crosses the antimeridian (MinLng > MaxLng).
+// The box west of the antimeridian (ending at 180) is first.
+func splitBox(box Box) []Box {
+	if box.MinLng <= box.MaxLng {
+		return []Box{box}
+	}
+	west, east := box, box
+	west.MaxLng = lngMax
+	east.MinLng = -lngMax
+	return []Box{west, east}
+}
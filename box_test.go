@@ -0,0 +1,53 @@
+package geohash
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCoverBox(t *testing.T) {
+	box := DecodeBox("dqcjq")
+	if res := CoverBox(box, 5); !slices.Equal(res, []string{"dqcjq"}) {
+		t.Errorf("CoverBox = %v, want [dqcjq]", res)
+	}
+
+	// Extending the box into the neighbors adds a row and column of cells.
+	box.MaxLat += box.Height() / 2
+	box.MaxLng += box.Width() / 2
+	want := []string{"dqcjq", "dqcjr", "dqcjw", "dqcjx"}
+	if res := CoverBox(box, 5); !slices.Equal(res, want) {
+		t.Errorf("CoverBox = %v, want %v", res, want)
+	}
+
+	// A box crossing the antimeridian is covered on both sides.
+	box = Box{MinLat: 1, MaxLat: 2, MinLng: 179, MaxLng: -179}
+	if res := CoverBox(box, 1); !slices.Equal(res, []string{"x", "8"}) {
+		t.Errorf("CoverBox = %v, want [x 8]", res)
+	}
+}
+
+func TestBoxContains(t *testing.T) {
+	box := DecodeBox("s")
+	if !box.Contains(0, 0) || box.Contains(45, 0) || box.Contains(0, 45) || box.Contains(-1, 0) {
+		t.Errorf("Contains = %+v, want min edges inclusive and max edges exclusive", box)
+	}
+}
+
+func TestPrecisionForSize(t *testing.T) {
+	testCases := []struct {
+		height, width float64
+		want          int
+	}{
+		{45, 45, 1},
+		{DecodeBox("dqcjq").Height(), DecodeBox("dqcjq").Width(), 5},
+		{1, 2, 3},
+		{1.0 / 24, 2.0 / 24, 5},
+		{0, 1, 12},
+	}
+
+	for _, c := range testCases {
+		if res := PrecisionForSize(c.height, c.width); res != c.want {
+			t.Errorf("PrecisionForSize(%g, %g) = %d, want %d", c.height, c.width, res, c.want)
+		}
+	}
+}
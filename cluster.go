@@ -0,0 +1,145 @@
+package geohash
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Cluster is a group of points drawn as a single marker.
+// Lat, Lng is the centroid of the points, so the marker is drawn where the points are rather than at the center of a cell.
+// Box is the bounding box of the points, which a map client can zoom to when the cluster is selected.
+type Cluster struct {
+	Hash  string  `json:"hash"`
+	Count int     `json:"count"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Box   Box     `json:"box"`
+}
+
+// ClusterOptions configures ClusterPoints.
+type ClusterOptions struct {
+	// Precision overrides the character precision of the cells points are grouped by. Defaults to PrecisionForZoom of the zoom.
+	Precision int
+
+	// MinCount merges clusters of fewer points into their largest neighboring cluster, so sparse areas are not drawn as
+	// many small markers along cell edges. Clusters without a neighboring cluster are kept. Defaults to 0, which merges nothing.
+	MinCount int
+}
+
+// PrecisionForZoom returns the character precision of geohash cells suited to clustering markers on a web map at a zoom level.
+// This is the finest precision whose cells are at least a quarter of the width of a 256 pixel map tile,
+// the last precision before cells become narrower than a quarter tile, so clusters are spaced at least 64 pixels apart.
+// At zoom 0 even precision 1 cells are narrower, and at zoom 28 and above precision 12 is returned.
+// Zoom levels are clamped to 0 to 32.
+func PrecisionForZoom(zoom int) int {
+	zoom = validate(0, 32, zoom)
+	for p := precisionMin; p < precisionMax; p++ {
+		if _, lngBits := gridBits((p + 1) * 5); lngBits > zoom+2 {
+			return p
+		}
+	}
+	return precisionMax
+}
+
+// ClusterPoints groups the points within viewport into clusters for drawing on a map at a zoom level.
+// Points are grouped by their geohash cell at the precision of opts.Precision or PrecisionForZoom of the zoom.
+// A viewport crossing the antimeridian (MinLng > MaxLng) is supported, and its edges are inclusive.
+// Clusters are ordered by decreasing count, with ties ordered by geohash.
+func ClusterPoints(points []Point, viewport Box, zoom int, opts ClusterOptions) []Cluster {
+	precision := opts.Precision
+	if precision == 0 {
+		precision = PrecisionForZoom(zoom)
+	}
+	precision = validate(precisionMin, precisionMax, precision)
+
+	clusters := map[string]*Cluster{}
+	for _, p := range points {
+		if !viewportContains(viewport, p) {
+			continue
+		}
+
+		hash := EncodeIntToStr(encodeIntClamp(p.Lat, p.Lng, precision*5), precision)
+		c, ok := clusters[hash]
+		if !ok {
+			c = &Cluster{Hash: hash, Box: Box{MinLat: p.Lat, MaxLat: p.Lat, MinLng: p.Lng, MaxLng: p.Lng}}
+			clusters[hash] = c
+		}
+		c.merge(Cluster{Count: 1, Lat: p.Lat, Lng: p.Lng, Box: Box{MinLat: p.Lat, MaxLat: p.Lat, MinLng: p.Lng, MaxLng: p.Lng}})
+	}
+
+	if opts.MinCount > 0 {
+		mergeClusters(clusters, opts.MinCount)
+	}
+
+	res := make([]Cluster, 0, len(clusters))
+	for _, c := range clusters {
+		res = append(res, *c)
+	}
+	slices.SortFunc(res, func(a, b Cluster) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Hash, b.Hash)
+	})
+	return res
+}
+
+// mergeClusters merges clusters of fewer than minCount points into their largest neighboring cluster.
+// Clusters are merged smallest first, so small clusters next to each other combine before joining larger ones.
+func mergeClusters(clusters map[string]*Cluster, minCount int) {
+	small := []*Cluster{}
+	for _, c := range clusters {
+		if c.Count < minCount {
+			small = append(small, c)
+		}
+	}
+	slices.SortFunc(small, func(a, b *Cluster) int {
+		if a.Count != b.Count {
+			return cmp.Compare(a.Count, b.Count)
+		}
+		return cmp.Compare(a.Hash, b.Hash)
+	})
+
+	for _, c := range small {
+		// A cluster that grew by absorbing others may have reached the minimum.
+		if c.Count >= minCount {
+			continue
+		}
+
+		var target *Cluster
+		for _, n := range Neighbors(c.Hash) {
+			// Neighbors wrap across the antimeridian, where averaging longitudes would move the centroid across the map.
+			if t, ok := clusters[n]; ok && math.Abs(t.Lng-c.Lng) <= 180 && (target == nil || t.Count > target.Count || t.Count == target.Count && t.Hash < target.Hash) {
+				target = t
+			}
+		}
+		if target != nil {
+			target.merge(*c)
+			delete(clusters, c.Hash)
+		}
+	}
+}
+
+// merge adds the points of o to c, weighting the centroids by count.
+func (c *Cluster) merge(o Cluster) {
+	total := float64(c.Count + o.Count)
+	c.Lat = (c.Lat*float64(c.Count) + o.Lat*float64(o.Count)) / total
+	c.Lng = (c.Lng*float64(c.Count) + o.Lng*float64(o.Count)) / total
+	c.Count += o.Count
+
+	c.Box.MinLat = math.Min(c.Box.MinLat, o.Box.MinLat)
+	c.Box.MaxLat = math.Max(c.Box.MaxLat, o.Box.MaxLat)
+	c.Box.MinLng = math.Min(c.Box.MinLng, o.Box.MinLng)
+	c.Box.MaxLng = math.Max(c.Box.MaxLng, o.Box.MaxLng)
+}
+
+// viewportContains reports whether p falls within the viewport, including its edges.
+func viewportContains(viewport Box, p Point) bool {
+	for _, b := range splitBox(viewport) {
+		if p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lng >= b.MinLng && p.Lng <= b.MaxLng {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrecisionForZoom(t *testing.T) {
+	tests := map[int]int{-1: 1, 0: 1, 2: 1, 3: 2, 6: 3, 8: 4, 11: 5, 13: 6, 16: 7, 18: 8, 20: 8, 21: 9, 32: 12, 40: 12}
+
+	for zoom, want := range tests {
+		if res := PrecisionForZoom(zoom); res != want {
+			t.Errorf("PrecisionForZoom(%d) = %d, want %d", zoom, res, want)
+		}
+	}
+
+	// Cells are at least a quarter tile wide, and cells of the next precision are narrower.
+	for zoom := 1; zoom < 28; zoom++ {
+		p := PrecisionForZoom(zoom)
+		quarter := 360 / math.Exp2(float64(zoom+2))
+		if width := DecodeBox(EncodePrecision(0, 0, p)).Width(); width < quarter {
+			t.Errorf("PrecisionForZoom(%d) = %d, %f wide, want at least %f", zoom, p, width, quarter)
+		}
+		if width := DecodeBox(EncodePrecision(0, 0, p+1)).Width(); width >= quarter {
+			t.Errorf("PrecisionForZoom(%d) = %d, next precision %f wide, want less than %f", zoom, p, width, quarter)
+		}
+	}
+}
+
+func TestClusterPoints(t *testing.T) {
+	points := []Point{
+		// Three points in dr5ru and one in dr5rs to the south.
+		{40.7400, -73.9900}, {40.7410, -73.9910}, {40.7420, -73.9880},
+		{40.7200, -73.9800},
+		// Outside the viewport.
+		{51.5074, -0.1278},
+	}
+	viewport := Box{MinLat: 40.5, MaxLat: 41, MinLng: -74.5, MaxLng: -73.5}
+
+	res := ClusterPoints(points, viewport, 12, ClusterOptions{})
+	if len(res) != 2 {
+		t.Fatalf("ClusterPoints = %+v, want 2 clusters", res)
+	}
+
+	c := res[0]
+	if c.Hash != "dr5ru" || c.Count != 3 {
+		t.Errorf("ClusterPoints = %s %d, want dr5ru 3", c.Hash, c.Count)
+	}
+	if math.Abs(c.Lat-40.741) > 1e-9 || math.Abs(c.Lng-(-73.9896666666667)) > 1e-9 {
+		t.Errorf("ClusterPoints centroid = %f, %f, want 40.741000, -73.989667", c.Lat, c.Lng)
+	}
+	want := Box{MinLat: 40.74, MaxLat: 40.742, MinLng: -73.991, MaxLng: -73.988}
+	if c.Box != want {
+		t.Errorf("ClusterPoints box = %+v, want %+v", c.Box, want)
+	}
+	if res[1].Hash != "dr5rs" || res[1].Count != 1 || res[1].Lat != 40.72 || res[1].Lng != -73.98 {
+		t.Errorf("ClusterPoints = %+v, want dr5rs with 1 point", res[1])
+	}
+
+	// The single point cluster merges into its neighbor.
+	res = ClusterPoints(points, viewport, 12, ClusterOptions{MinCount: 2})
+	if len(res) != 1 || res[0].Hash != "dr5ru" || res[0].Count != 4 {
+		t.Fatalf("ClusterPoints = %+v, want dr5ru with 4 points", res)
+	}
+	if math.Abs(res[0].Lat-40.73575) > 1e-9 || res[0].Box.MinLat != 40.72 {
+		t.Errorf("ClusterPoints = %+v, want centroid lat 40.73575 and min lat 40.72", res[0])
+	}
+
+	// Precision overrides the zoom.
+	if res := ClusterPoints(points, viewport, 12, ClusterOptions{Precision: 3}); len(res) != 1 || res[0].Hash != "dr5" || res[0].Count != 4 {
+		t.Errorf("ClusterPoints = %+v, want dr5 with 4 points", res)
+	}
+}
+
+func TestClusterPointsAntimeridian(t *testing.T) {
+	points := []Point{{-16.5, 179.9}, {-16.5, -179.9}, {-16.6, -179.9}}
+	viewport := Box{MinLat: -20, MaxLat: -10, MinLng: 170, MaxLng: -170}
+
+	res := ClusterPoints(points, viewport, 8, ClusterOptions{MinCount: 2})
+	if len(res) != 2 {
+		t.Fatalf("ClusterPoints = %+v, want 2 clusters", res)
+	}
+	if res[0].Count != 2 || res[0].Lng != -179.9 || res[1].Count != 1 || res[1].Lng != 179.9 {
+		t.Errorf("ClusterPoints = %+v, want clusters on each side of the antimeridian", res)
+	}
+
+	if res := ClusterPoints(points, Box{MinLat: -20, MaxLat: -10, MinLng: -170, MaxLng: 170}, 8, ClusterOptions{}); len(res) != 0 {
+		t.Errorf("ClusterPoints = %+v, want no clusters", res)
+	}
+}
+
+func TestClusterPointsMaxEdge(t *testing.T) {
+	// Viewport edges are inclusive, so points on the north and east edges are clustered in the northeast corner cell.
+	points := []Point{{90, 180}, {89.99, 179.99}}
+	viewport := Box{MinLat: 80, MaxLat: 90, MinLng: 170, MaxLng: 180}
+
+	res := ClusterPoints(points, viewport, 0, ClusterOptions{Precision: 3})
+	if len(res) != 1 || res[0].Hash != "zzz" || res[0].Count != 2 {
+		t.Errorf("ClusterPoints = %+v, want one cluster zzz of 2", res)
+	}
+}
+
+func BenchmarkClusterPoints(b *testing.B) {
+	points := testPoints(10000, 1)
+	viewport := Box{MinLat: 40, MaxLat: 41, MinLng: -75, MaxLng: -73}
+	for n := 0; n < b.N; n++ {
+		ClusterPoints(points, viewport, 10, ClusterOptions{MinCount: 5})
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bbailey1024/geohash"
+)
+
+// polygonScan limits the cells of the polygons' bounding boxes examined by coverPolygons, as a multiple of the cell limit.
+const polygonScan = 16
+
+// errTooManyCells returns the error for a cover exceeding limit cells.
+func errTooManyCells(limit int) error {
+	return fmt.Errorf("%w: cover exceeds %d cells, use a lower precision", errBadRequest, limit)
+}
+
+// coverCircle returns the cells of precision within radius meters of lat, lng, nearest first.
+// The cells are found with geohash.Spiral, stopping at the first cell beyond the radius.
+func coverCircle(lat, lng, radius float64, precision, limit int) ([]string, error) {
+	cells := []string{}
+	for hash, d := range geohash.Spiral(lat, lng, precision) {
+		if d > radius {
+			break
+		}
+		if len(cells) == limit {
+			return nil, errTooManyCells(limit)
+		}
+		cells = append(cells, hash)
+	}
+	return cells, nil
+}
+
+// polygon is a GeoJSON polygon: an exterior ring followed by any holes, each closed with the first point repeated.
+type polygon [][]geohash.Point
+
+// geometry is a GeoJSON Polygon or MultiPolygon geometry, or a Feature with one of those geometries.
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geometry       `json:"geometry"`
+}
+
+// polygons returns the polygons of the geometry, validating their rings and coordinates.
+func (g *geometry) polygons() ([]polygon, error) {
+	var coords [][][][]float64
+	switch g.Type {
+	case "Feature":
+		if g.Geometry == nil || g.Geometry.Type == "Feature" {
+			return nil, fmt.Errorf("%w: feature requires a Polygon or MultiPolygon geometry", errBadRequest)
+		}
+		return g.Geometry.polygons()
+	case "Polygon":
+		var p [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return nil, fmt.Errorf("%w: invalid Polygon coordinates", errBadRequest)
+		}
+		coords = [][][][]float64{p}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("%w: invalid MultiPolygon coordinates", errBadRequest)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported GeoJSON type %q, want Polygon, MultiPolygon or Feature", errBadRequest, g.Type)
+	}
+
+	polygons := make([]polygon, len(coords))
+	for i, rings := range coords {
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("%w: polygon %d has no rings", errBadRequest, i)
+		}
+		for _, ring := range rings {
+			// A closed ring has at least 3 distinct positions and repeats the first as the last.
+			if len(ring) < 4 {
+				return nil, fmt.Errorf("%w: polygon %d has a ring of fewer than 4 positions", errBadRequest, i)
+			}
+
+			// GeoJSON positions are lng, lat, optionally followed by an altitude.
+			points := make([]geohash.Point, len(ring))
+			for j, pos := range ring {
+				if len(pos) < 2 || !(pos[0] >= -180 && pos[0] <= 180) || !(pos[1] >= -90 && pos[1] <= 90) {
+					return nil, fmt.Errorf("%w: polygon %d has an invalid position %v", errBadRequest, i, pos)
+				}
+				points[j] = geohash.Point{Lat: pos[1], Lng: pos[0]}
+			}
+			if points[0] != points[len(points)-1] {
+				return nil, fmt.Errorf("%w: polygon %d has a ring that is not closed", errBadRequest, i)
+			}
+			polygons[i] = append(polygons[i], points)
+		}
+	}
+	return polygons, nil
+}
+
+// coverPolygons returns the cells of precision overlapping any of the polygons, in the order of the polygons,
+// each ordered as geohash.CellsInBox. Cells of each polygon's bounding box are kept when their center is inside
+// the polygon or an edge of the polygon crosses them, so cells within holes are excluded.
+// Polygons crossing the antimeridian are not supported, as GeoJSON splits them into a MultiPolygon at 180.
+// The limits on cells returned and cells examined apply to the whole request rather than to each polygon,
+// so a MultiPolygon of many small or repeated polygons cannot multiply the work done.
+func coverPolygons(polygons []polygon, precision, limit int) ([]string, error) {
+	cells := []string{}
+	seen := map[string]bool{}
+	scanned := 0
+
+	for _, p := range polygons {
+		bounds := geohash.Box{MinLat: 90, MaxLat: -90, MinLng: 180, MaxLng: -180}
+		for _, pt := range p[0] {
+			bounds.MinLat, bounds.MaxLat = min(bounds.MinLat, pt.Lat), max(bounds.MaxLat, pt.Lat)
+			bounds.MinLng, bounds.MaxLng = min(bounds.MinLng, pt.Lng), max(bounds.MaxLng, pt.Lng)
+		}
+
+		for hash := range geohash.CellsInBox(bounds, precision) {
+			if scanned++; scanned > polygonScan*limit {
+				return nil, fmt.Errorf("%w: polygon bounding boxes exceed %d cells, use a lower precision", errBadRequest, polygonScan*limit)
+			}
+			if seen[hash] || !p.overlaps(geohash.DecodeBox(hash)) {
+				continue
+			}
+			if len(cells) == limit {
+				return nil, errTooManyCells(limit)
+			}
+			seen[hash] = true
+			cells = append(cells, hash)
+		}
+	}
+	return cells, nil
+}
+
+// overlaps reports whether the polygon overlaps box: either an edge crosses box, or box lies entirely inside the polygon,
+// which is tested with the center of box. A polygon entirely inside box has edges within box.
+func (p polygon) overlaps(box geohash.Box) bool {
+	for _, ring := range p {
+		for i := 0; i+1 < len(ring); i++ {
+			if segmentInBox(ring[i], ring[i+1], box) {
+				return true
+			}
+		}
+	}
+
+	lat, lng := box.Center()
+	return p.contains(lat, lng)
+}
+
+// contains reports whether lat, lng is inside the polygon using the even-odd rule, so points within holes are outside.
+func (p polygon) contains(lat, lng float64) bool {
+	inside := false
+	for _, ring := range p {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a.Lat > lat) != (b.Lat > lat) && lng < (b.Lng-a.Lng)*(lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// segmentInBox reports whether any part of the segment from a to b lies within box,
+// by clipping the segment to each edge of box in turn (Liang-Barsky).
+func segmentInBox(a, b geohash.Point, box geohash.Box) bool {
+	t0, t1 := 0.0, 1.0
+	dLng, dLat := b.Lng-a.Lng, b.Lat-a.Lat
+
+	// Each edge is given as p*t <= q, clipping t to the part of the segment inside that edge.
+	for _, e := range [4][2]float64{
+		{-dLng, a.Lng - box.MinLng},
+		{dLng, box.MaxLng - a.Lng},
+		{-dLat, a.Lat - box.MinLat},
+		{dLat, box.MaxLat - a.Lat},
+	} {
+		p, q := e[0], e[1]
+		switch {
+		case p == 0:
+			if q < 0 {
+				return false
+			}
+		case p < 0:
+			t0 = max(t0, q/p)
+		default:
+			t1 = min(t1, q/p)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
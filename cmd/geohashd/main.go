@@ -0,0 +1,39 @@
+// Command geohashd serves the geohash package over HTTP, so services written in other languages share its geohash semantics.
+// It is intended to run as a local sidecar. Every endpoint accepts query parameters and responds with JSON.
+//
+//	GET  /encode?lat=40.7128&lng=-74.0060&precision=9
+//	GET  /decode?hash=dr5regw3p
+//	GET  /box?hash=dr5regw3p
+//	GET  /neighbors?hash=dr5regw3p
+//	GET  /cover?lat=40.7128&lng=-74.0060&radius=500&precision=7
+//	GET  /cover?minLat=40.70&minLng=-74.02&maxLat=40.80&maxLng=-73.93&precision=6
+//	POST /cover?precision=6 with a GeoJSON Polygon, MultiPolygon or Feature body
+//	GET  /convert?mgrs=18TWL8395107223
+//
+// Invalid coordinates, hashes and parameters respond with 400 Bad Request and a JSON error message.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	maxCells := flag.Int("max-cells", 10000, "maximum number of cells returned by /cover")
+	flag.Parse()
+	if *maxCells < 0 {
+		log.Fatalf("geohashd: -max-cells must not be negative, got %d", *maxCells)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(*maxCells),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("geohashd listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bbailey1024/geohash"
+	"github.com/bbailey1024/geohash/maidenhead"
+	"github.com/bbailey1024/geohash/mgrs"
+	"github.com/bbailey1024/geohash/olc"
+)
+
+// maxBody limits the size of a GeoJSON request body.
+const maxBody = 1 << 20
+
+// errBadRequest is wrapped by errors caused by invalid input, which respond with 400 Bad Request.
+var errBadRequest = errors.New("bad request")
+
+// server holds the configuration shared by the handlers.
+type server struct {
+	maxCells int
+}
+
+// newServer returns the handler serving every endpoint.
+func newServer(maxCells int) http.Handler {
+	s := &server{maxCells: maxCells}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /encode", s.handle(s.encode))
+	mux.HandleFunc("GET /decode", s.handle(s.decode))
+	mux.HandleFunc("GET /box", s.handle(s.box))
+	mux.HandleFunc("GET /neighbors", s.handle(s.neighbors))
+	mux.HandleFunc("GET /cover", s.handle(s.cover))
+	mux.HandleFunc("POST /cover", s.handle(s.cover))
+	mux.HandleFunc("GET /convert", s.handle(s.convert))
+	return mux
+}
+
+// handle adapts a function returning a response value to an http.HandlerFunc writing it as JSON.
+// Errors wrapping errBadRequest or geohash.ErrInvalidHash respond with 400, and other errors with 500.
+func (s *server) handle(fn func(*http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := fn(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errBadRequest) || errors.Is(err, geohash.ErrInvalidHash) {
+				status = http.StatusBadRequest
+			}
+			writeJSON(w, status, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type encodeResponse struct {
+	Hash string `json:"hash"`
+}
+
+// encode returns the geohash of lat, lng at precision, defaulting to 12 characters.
+func (s *server) encode(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	lat, lng, err := parsePoint(q, "lat", "lng")
+	if err != nil {
+		return nil, err
+	}
+	precision, err := parsePrecision(q)
+	if err != nil {
+		return nil, err
+	}
+	// WGS84 clamps the max edges of 90 and 180 into the last row and column, rather than overflowing to the opposite corner.
+	return encodeResponse{Hash: geohash.WGS84.EncodePrecision(lat, lng, precision)}, nil
+}
+
+type decodeResponse struct {
+	Lat    float64 `json:"lat"`
+	Lng    float64 `json:"lng"`
+	LatErr float64 `json:"latErr"`
+	LngErr float64 `json:"lngErr"`
+}
+
+// decode returns the center of the cell of hash and the maximum error of the center in each direction.
+func (s *server) decode(r *http.Request) (any, error) {
+	hash, err := parseHash(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	lat, lng := geohash.Decode(hash)
+	box := geohash.DecodeBox(hash)
+	return decodeResponse{Lat: lat, Lng: lng, LatErr: box.Height() / 2, LngErr: box.Width() / 2}, nil
+}
+
+// box returns the bounding box of the cell of hash.
+func (s *server) box(r *http.Request) (any, error) {
+	hash, err := parseHash(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	return geohash.DecodeBox(hash), nil
+}
+
+type neighborsResponse struct {
+	Neighbors []string `json:"neighbors"`
+}
+
+// neighbors returns the neighbors of the cell of hash, ordered clockwise from north as geohash.Neighbors.
+func (s *server) neighbors(r *http.Request) (any, error) {
+	hash, err := parseHash(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	return neighborsResponse{Neighbors: geohash.Neighbors(hash)}, nil
+}
+
+type coverResponse struct {
+	Cells []string `json:"cells"`
+}
+
+// cover returns the cells covering a GeoJSON polygon posted as the body, a circle of radius meters around lat, lng,
+// or a box of minLat, minLng, maxLat, maxLng.
+func (s *server) cover(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	precision, err := parsePrecision(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var cells []string
+	switch {
+	case r.Method == http.MethodPost:
+		var g geometry
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxBody)).Decode(&g); err != nil {
+			return nil, fmt.Errorf("%w: invalid GeoJSON: %s", errBadRequest, err.Error())
+		}
+		polygons, err := g.polygons()
+		if err != nil {
+			return nil, err
+		}
+		cells, err = coverPolygons(polygons, precision, s.maxCells)
+		if err != nil {
+			return nil, err
+		}
+
+	case q.Has("radius"):
+		lat, lng, err := parsePoint(q, "lat", "lng")
+		if err != nil {
+			return nil, err
+		}
+		radius, err := parseFloat(q, "radius", 0, math.MaxFloat64)
+		if err != nil {
+			return nil, err
+		}
+		cells, err = coverCircle(lat, lng, radius, precision, s.maxCells)
+		if err != nil {
+			return nil, err
+		}
+
+	case q.Has("minLat"):
+		minLat, minLng, err := parsePoint(q, "minLat", "minLng")
+		if err != nil {
+			return nil, err
+		}
+		maxLat, maxLng, err := parsePoint(q, "maxLat", "maxLng")
+		if err != nil {
+			return nil, err
+		}
+		if minLat > maxLat {
+			return nil, fmt.Errorf("%w: minLat exceeds maxLat", errBadRequest)
+		}
+
+		// A box with minLng greater than maxLng crosses the antimeridian.
+		box := geohash.Box{MinLat: minLat, MaxLat: maxLat, MinLng: minLng, MaxLng: maxLng}
+		cells = []string{}
+		for hash := range geohash.CellsInBox(box, precision) {
+			if len(cells) == s.maxCells {
+				return nil, errTooManyCells(s.maxCells)
+			}
+			cells = append(cells, hash)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: cover requires a GeoJSON body, lat, lng and radius, or minLat, minLng, maxLat and maxLng", errBadRequest)
+	}
+
+	return coverResponse{Cells: cells}, nil
+}
+
+type convertResponse struct {
+	Geohash    string      `json:"geohash"`
+	Int        uint64      `json:"int"`
+	Bits       int         `json:"bits"`
+	Box        geohash.Box `json:"box"`
+	OLC        string      `json:"olc"`
+	Maidenhead string      `json:"maidenhead"`
+	MGRS       string      `json:"mgrs,omitempty"`
+	Tile       tileOutput  `json:"tile"`
+}
+
+type tileOutput struct {
+	X       uint32 `json:"x"`
+	Y       uint32 `json:"y"`
+	Z       int    `json:"z"`
+	Quadkey string `json:"quadkey"`
+}
+
+// convert converts a location given as exactly one of geohash, olc, maidenhead or mgrs to each of the others.
+// Each conversion describes the center of the location at the precision closest in size, as the subpackages do.
+// The MGRS reference is omitted in the polar regions it does not cover.
+func (s *server) convert(r *http.Request) (any, error) {
+	q := r.URL.Query()
+
+	var inputs []string
+	for _, k := range []string{"geohash", "olc", "maidenhead", "mgrs"} {
+		if q.Has(k) {
+			inputs = append(inputs, k)
+		}
+	}
+	if len(inputs) != 1 {
+		return nil, fmt.Errorf("%w: convert requires exactly one of geohash, olc, maidenhead or mgrs", errBadRequest)
+	}
+
+	var hash string
+	var err error
+	switch v := q.Get(inputs[0]); inputs[0] {
+	case "geohash":
+		hash, err = parseHash(q)
+	case "olc":
+		hash, err = olcGeohash(v)
+	case "maidenhead":
+		hash, err = maidenhead.Geohash(v)
+	case "mgrs":
+		hash, err = mgrs.Geohash(v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errBadRequest, err.Error())
+	}
+
+	h, _ := geohash.ParseHash(hash)
+	box := geohash.DecodeBox(hash)
+	lat, lng := box.Center()
+	res := convertResponse{Geohash: hash, Int: h.Int(), Bits: h.Bits(), Box: box, OLC: olcFromGeohash(box)}
+
+	if res.Maidenhead, err = maidenhead.FromGeohash(hash); err != nil {
+		return nil, err
+	}
+	if ref, err := mgrs.FromGeohash(hash); err == nil {
+		res.MGRS = ref
+	} else if !errors.Is(err, mgrs.ErrPolar) {
+		return nil, err
+	}
+
+	// The tile containing the center at the zoom whose tiles are closest in width to the cell.
+	zoom := int(math.Round(math.Log2(360 / box.Width())))
+	t := geohash.TileFromPoint(lat, lng, zoom)
+	res.Tile = tileOutput{X: t.X, Y: t.Y, Z: t.Z, Quadkey: t.Quadkey()}
+
+	return res, nil
+}
+
+// olcGeohash returns the geohash of the center of an Open Location Code at the precision closest in size to its area.
+func olcGeohash(code string) (string, error) {
+	area, err := olc.Decode(code)
+	if err != nil {
+		return "", err
+	}
+	lat, lng := area.Center()
+	return geohash.WGS84.EncodePrecision(lat, lng, geohash.PrecisionForSize(area.Height(), area.Width())), nil
+}
+
+// olcFromGeohash returns the Open Location Code of the center of box with the length whose area is closest to box,
+// compared on a log scale as geohash.PrecisionForSize does.
+func olcFromGeohash(box geohash.Box) string {
+	lat, lng := box.Center()
+	area := box.Height() * box.Width()
+
+	best, bestDiff := "", math.Inf(1)
+	for _, n := range []int{2, 4, 6, 8, 10, 11, 12, 13, 14, 15} {
+		code := olc.Encode(lat, lng, n)
+		a, err := olc.Decode(code)
+		if err != nil {
+			continue
+		}
+		if diff := math.Abs(math.Log(a.Height() * a.Width() / area)); diff < bestDiff {
+			best, bestDiff = code, diff
+		}
+	}
+	return best
+}
+
+// parsePoint returns the coordinates of the lat and lng parameters, which must be within [-90, 90] and [-180, 180].
+func parsePoint(q url.Values, latKey, lngKey string) (float64, float64, error) {
+	lat, err := parseFloat(q, latKey, -90, 90)
+	if err != nil {
+		return 0, 0, err
+	}
+	lng, err := parseFloat(q, lngKey, -180, 180)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
+
+// parseFloat returns the required float parameter key, which must be within [min, max].
+func parseFloat(q url.Values, key string, min, max float64) (float64, error) {
+	if !q.Has(key) {
+		return 0, fmt.Errorf("%w: missing %s", errBadRequest, key)
+	}
+	v, err := strconv.ParseFloat(q.Get(key), 64)
+	if err != nil || !(v >= min && v <= max) {
+		return 0, fmt.Errorf("%w: %s must be a number from %g to %g", errBadRequest, key, min, max)
+	}
+	return v, nil
+}
+
+// parsePrecision returns the optional precision parameter, which must be 1 to 12 and defaults to 12.
+func parsePrecision(q url.Values) (int, error) {
+	if !q.Has("precision") {
+		return 12, nil
+	}
+	p, err := strconv.Atoi(q.Get("precision"))
+	if err != nil || p < 1 || p > 12 {
+		return 0, fmt.Errorf("%w: precision must be an integer from 1 to 12", errBadRequest)
+	}
+	return p, nil
+}
+
+// parseHash returns the required hash parameter, which must be a geohash string of 1 to 12 characters.
+// The geohash parameter is accepted in place of hash.
+func parseHash(q url.Values) (string, error) {
+	hash := q.Get("hash")
+	if !q.Has("hash") {
+		hash = q.Get("geohash")
+	}
+	if hash == "" {
+		return "", fmt.Errorf("%w: missing hash", errBadRequest)
+	}
+	if _, err := geohash.ParseHash(hash); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
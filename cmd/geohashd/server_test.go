@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bbailey1024/geohash"
+)
+
+// get requests path from the server and decodes the JSON response into v, returning the status code.
+func get(t *testing.T, srv http.Handler, method, path, body string, v any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); rec.Code != http.StatusMethodNotAllowed && ct != "application/json" {
+		t.Errorf("%s %s Content-Type = %q, want application/json", method, path, ct)
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Errorf("%s %s = %s, invalid JSON %s", method, path, rec.Body.String(), err.Error())
+		}
+	}
+	return rec.Code
+}
+
+func TestEncodeDecode(t *testing.T) {
+	srv := newServer(1000)
+
+	var enc encodeResponse
+	if code := get(t, srv, "GET", "/encode?lat=57.64911&lng=10.40744&precision=11", "", &enc); code != http.StatusOK || enc.Hash != "u4pruydqqvj" {
+		t.Errorf("/encode = %d %+v, want 200 u4pruydqqvj", code, enc)
+	}
+	if code := get(t, srv, "GET", "/encode?lat=57.64911&lng=10.40744", "", &enc); code != http.StatusOK || enc.Hash != geohash.Encode(57.64911, 10.40744) {
+		t.Errorf("/encode = %d %+v, want 200 %s", code, enc, geohash.Encode(57.64911, 10.40744))
+	}
+
+	var dec decodeResponse
+	lat, lng := geohash.Decode("u4pruydqqvj")
+	if code := get(t, srv, "GET", "/decode?hash=u4pruydqqvj", "", &dec); code != http.StatusOK || dec.Lat != lat || dec.Lng != lng || dec.LatErr <= 0 {
+		t.Errorf("/decode = %d %+v, want 200 %f, %f", code, dec, lat, lng)
+	}
+
+	var box geohash.Box
+	if code := get(t, srv, "GET", "/box?hash=u4pru", "", &box); code != http.StatusOK || box != geohash.DecodeBox("u4pru") {
+		t.Errorf("/box = %d %+v, want 200 %+v", code, box, geohash.DecodeBox("u4pru"))
+	}
+
+	var n neighborsResponse
+	if code := get(t, srv, "GET", "/neighbors?hash=u4pru", "", &n); code != http.StatusOK || !slices.Equal(n.Neighbors, geohash.Neighbors("u4pru")) {
+		t.Errorf("/neighbors = %d %+v, want 200 %v", code, n, geohash.Neighbors("u4pru"))
+	}
+}
+
+func TestMaxEdge(t *testing.T) {
+	srv := newServer(1000)
+
+	// Coordinates on the max edges are valid and encode to the northernmost row and easternmost column.
+	tests := map[string]string{
+		"/encode?lat=90&lng=180":             "zzzzzzzzzzzz",
+		"/encode?lat=90&lng=180&precision=5": "zzzzz",
+		"/encode?lat=90&lng=0&precision=5":   "upbpb",
+		"/encode?lat=0&lng=180&precision=5":  "xbpbp",
+	}
+	for path, want := range tests {
+		var enc encodeResponse
+		if code := get(t, srv, "GET", path, "", &enc); code != http.StatusOK || enc.Hash != want {
+			t.Errorf("%s = %d %+v, want 200 %s", path, code, enc, want)
+		}
+	}
+
+	var res coverResponse
+	want := geohash.WGS84.EncodePrecision(45, 180, 5)
+	if code := get(t, srv, "GET", "/cover?lat=45&lng=180&radius=1&precision=5", "", &res); code != http.StatusOK || len(res.Cells) == 0 || res.Cells[0] != want {
+		t.Errorf("/cover circle = %d %v, want 200 starting with %s", code, res.Cells, want)
+	}
+	if !strings.HasPrefix(want, "z") {
+		t.Errorf("WGS84.EncodePrecision(45, 180) = %s, want the z quadrant", want)
+	}
+}
+
+func TestBadRequest(t *testing.T) {
+	srv := newServer(100)
+
+	paths := []string{
+		"/encode",
+		"/encode?lat=91&lng=0",
+		"/encode?lat=0&lng=-180.5",
+		"/encode?lat=abc&lng=0",
+		"/encode?lat=NaN&lng=0",
+		"/encode?lat=0&lng=0&precision=13",
+		"/encode?lat=0&lng=0&precision=x",
+		"/decode",
+		"/decode?hash=u4pra",
+		"/decode?hash=u4pruydqqvjjj",
+		"/box?hash=AAA",
+		"/neighbors?hash=",
+		"/cover",
+		"/cover?lat=0&lng=0&radius=-1",
+		"/cover?lat=0&lng=0&radius=100000&precision=7",
+		"/cover?minLat=10&minLng=0&maxLat=0&maxLng=1",
+		"/cover?minLat=0&minLng=0&maxLat=10&maxLng=10&precision=5",
+		"/convert",
+		"/convert?geohash=u4pru&olc=9F",
+		"/convert?olc=invalid",
+		"/convert?maidenhead=ZZ99",
+		"/convert?mgrs=99ZZZ",
+	}
+
+	for _, path := range paths {
+		var res errorResponse
+		if code := get(t, srv, "GET", path, "", &res); code != http.StatusBadRequest || res.Error == "" {
+			t.Errorf("GET %s = %d %+v, want 400 with an error", path, code, res)
+		}
+	}
+
+	bodies := []string{
+		`{`,
+		`{"type":"Point","coordinates":[0,0]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[200,0],[1,1],[0,0]]]}`,
+		`{"type":"Feature","geometry":null}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,0]]]}`,
+	}
+	for _, body := range bodies {
+		var res errorResponse
+		if code := get(t, srv, "POST", "/cover?precision=5", body, &res); code != http.StatusBadRequest || res.Error == "" {
+			t.Errorf("POST /cover %s = %d %+v, want 400 with an error", body, code, res)
+		}
+	}
+
+	if code := get(t, srv, "DELETE", "/encode", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /encode = %d, want 405", code)
+	}
+}
+
+func TestCover(t *testing.T) {
+	srv := newServer(10000)
+
+	// A box matches geohash.CoverBox.
+	var res coverResponse
+	want := geohash.CoverBox(geohash.Box{MinLat: 40.70, MaxLat: 40.80, MinLng: -74.02, MaxLng: -73.93}, 6)
+	if code := get(t, srv, "GET", "/cover?minLat=40.70&minLng=-74.02&maxLat=40.80&maxLng=-73.93&precision=6", "", &res); code != http.StatusOK || !slices.Equal(res.Cells, want) {
+		t.Errorf("/cover box = %d %d cells, want 200 %d cells", code, len(res.Cells), len(want))
+	}
+
+	// A circle starts with the cell of its center and includes its neighbors when they are within the radius.
+	if code := get(t, srv, "GET", "/cover?lat=40.7128&lng=-74.0060&radius=500&precision=7", "", &res); code != http.StatusOK || len(res.Cells) < 9 || res.Cells[0] != geohash.EncodePrecision(40.7128, -74.0060, 7) {
+		t.Errorf("/cover circle = %d %v, want 200 starting with %s", code, res.Cells, geohash.EncodePrecision(40.7128, -74.0060, 7))
+	}
+	for _, n := range geohash.Neighbors(res.Cells[0]) {
+		if !slices.Contains(res.Cells, n) {
+			t.Errorf("/cover circle = %v, missing neighbor %s", res.Cells, n)
+		}
+	}
+	if code := get(t, srv, "GET", "/cover?lat=40.7128&lng=-74.0060&radius=0&precision=7", "", &res); code != http.StatusOK || len(res.Cells) != 1 {
+		t.Errorf("/cover circle = %d %v, want 200 with 1 cell", code, res.Cells)
+	}
+
+	// A square polygon aligned to the cells of dr5r covers exactly its 32 children.
+	b := geohash.DecodeBox("dr5r")
+	square := [][2]float64{{b.MinLng, b.MinLat}, {b.MaxLng, b.MinLat}, {b.MaxLng, b.MaxLat}, {b.MinLng, b.MaxLat}, {b.MinLng, b.MinLat}}
+	inset := func(d float64) [][2]float64 {
+		return [][2]float64{{b.MinLng + d, b.MinLat + d}, {b.MaxLng - d, b.MinLat + d}, {b.MaxLng - d, b.MaxLat - d}, {b.MinLng + d, b.MaxLat - d}, {b.MinLng + d, b.MinLat + d}}
+	}
+	poly, _ := json.Marshal(map[string]any{"type": "Polygon", "coordinates": [][][2]float64{inset(1e-9)}})
+	if code := get(t, srv, "POST", "/cover?precision=5", string(poly), &res); code != http.StatusOK || !slices.Equal(res.Cells, geohash.CoverBox(b, 5)) {
+		t.Errorf("/cover polygon = %d %v, want 200 children of dr5r", code, res.Cells)
+	}
+
+	// A hole leaving a border of a quarter of the height excludes the cells entirely within it, as a Feature.
+	// At 6 characters dr5r has 32 rows and 32 columns, and the hole edges cross rows 8 and 23 and columns 4 and 27.
+	feature, _ := json.Marshal(map[string]any{
+		"type":     "Feature",
+		"geometry": map[string]any{"type": "Polygon", "coordinates": [][][2]float64{square, inset(b.Height()/4 + 1e-9)}},
+	})
+	if code := get(t, srv, "POST", "/cover?precision=6", string(feature), &res); code != http.StatusOK || len(res.Cells) != 1024-14*22 {
+		t.Errorf("/cover feature = %d %d cells, want 200 %d cells", code, len(res.Cells), 1024-14*22)
+	}
+
+	// The polygons of a MultiPolygon are covered in order without duplicates.
+	multi, _ := json.Marshal(map[string]any{"type": "MultiPolygon", "coordinates": [][][][2]float64{{inset(1e-9)}, {inset(1e-9)}}})
+	if code := get(t, srv, "POST", "/cover?precision=5", string(multi), &res); code != http.StatusOK || len(res.Cells) != 32 {
+		t.Errorf("/cover multipolygon = %d %d cells, want 200 32 cells", code, len(res.Cells))
+	}
+
+	// The cells examined are limited across the polygons of a request, not per polygon:
+	// 17 copies of a polygon within a single cell examine 17 cells, exceeding 16 times a limit of 1.
+	small := [][2]float64{{b.MinLng + 1e-3, b.MinLat + 1e-3}, {b.MinLng + 2e-3, b.MinLat + 1e-3}, {b.MinLng + 1e-3, b.MinLat + 2e-3}, {b.MinLng + 1e-3, b.MinLat + 1e-3}}
+	for _, c := range []struct {
+		copies int
+		want   int
+	}{{16, http.StatusOK}, {17, http.StatusBadRequest}} {
+		coords := make([][][][2]float64, c.copies)
+		for i := range coords {
+			coords[i] = [][][2]float64{small}
+		}
+		multi, _ := json.Marshal(map[string]any{"type": "MultiPolygon", "coordinates": coords})
+		if code := get(t, newServer(1), "POST", "/cover?precision=5", string(multi), nil); code != c.want {
+			t.Errorf("/cover %d polygons = %d, want %d", c.copies, code, c.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	srv := newServer(100)
+
+	var res convertResponse
+	if code := get(t, srv, "GET", "/convert?geohash=u4pru", "", &res); code != http.StatusOK {
+		t.Fatalf("/convert = %d, want 200", code)
+	}
+	if res.Geohash != "u4pru" || res.Int != geohash.EncodeIntPrecision(57.64911, 10.40744, 25) || res.Bits != 25 || res.Box != geohash.DecodeBox("u4pru") {
+		t.Errorf("/convert = %+v, want u4pru", res)
+	}
+	want := convertResponse{OLC: "9F9GJ900+", Maidenhead: "JO57ep", MGRS: "32VNJ88", Tile: tileOutput{X: 4332, Y: 2482, Z: 13, Quadkey: "1200231321120"}}
+	if res.OLC != want.OLC || res.Maidenhead != want.Maidenhead || res.MGRS != want.MGRS || res.Tile != want.Tile {
+		t.Errorf("/convert = %+v, want %+v", res, want)
+	}
+
+	// Each representation converts back to a geohash near the original cell, at the precision closest to its own size.
+	for _, path := range []string{"/convert?olc=" + strings.ReplaceAll(res.OLC, "+", "%2B"), "/convert?maidenhead=" + res.Maidenhead, "/convert?mgrs=" + res.MGRS} {
+		var back convertResponse
+		if code := get(t, srv, "GET", path, "", &back); code != http.StatusOK || !strings.HasPrefix(back.Geohash, "u4p") {
+			t.Errorf("GET %s = %d %+v, want a geohash within u4p", path, code, back)
+		}
+	}
+
+	// MGRS does not cover the poles.
+	var polar convertResponse
+	if code := get(t, srv, "GET", "/convert?geohash=zzz", "", &polar); code != http.StatusOK || polar.MGRS != "" || polar.Maidenhead != "RR99" {
+		t.Errorf("/convert = %d %+v, want 200 RR99 without mgrs", code, polar)
+	}
+}
@@ -0,0 +1,99 @@
+package geohash
+
+import "math"
+
+// metersPerDegree is the length of a degree of latitude on a sphere of the Earth's mean radius.
+const metersPerDegree = earthRadius * math.Pi / 180
+
+// haversine returns the great-circle distance between a and b in meters.
+func haversine(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// bearing returns the initial bearing from a to b in radians, clockwise from north.
+func bearing(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Atan2(y, x)
+}
+
+// interpolate returns the point a fraction t of the way along the great circle from a to b.
+// The points are converted to unit vectors and combined using spherical linear interpolation.
+func interpolate(a, b Point, t float64) Point {
+	d := haversine(a, b) / earthRadius
+	if d == 0 {
+		return a
+	}
+
+	lat1, lng1 := a.Lat*math.Pi/180, a.Lng*math.Pi/180
+	lat2, lng2 := b.Lat*math.Pi/180, b.Lng*math.Pi/180
+	wa := math.Sin((1-t)*d) / math.Sin(d)
+	wb := math.Sin(t*d) / math.Sin(d)
+
+	x := wa*math.Cos(lat1)*math.Cos(lng1) + wb*math.Cos(lat2)*math.Cos(lng2)
+	y := wa*math.Cos(lat1)*math.Sin(lng1) + wb*math.Cos(lat2)*math.Sin(lng2)
+	z := wa*math.Sin(lat1) + wb*math.Sin(lat2)
+
+	return Point{
+		Lat: math.Atan2(z, math.Sqrt(x*x+y*y)) * 180 / math.Pi,
+		Lng: math.Atan2(y, x) * 180 / math.Pi,
+	}
+}
+
+// segmentDistance returns the distance in meters from p to the nearest point of the great-circle segment from a to b.
+// The cross-track distance is used when p projects onto the segment, otherwise the distance to the nearest end.
+func segmentDistance(p, a, b Point) float64 {
+	dap := haversine(a, p)
+	if a == b || dap == 0 {
+		return dap
+	}
+
+	// The angle between the segment and the path to p determines whether p is beyond a.
+	theta := bearing(a, p) - bearing(a, b)
+	if math.Cos(theta) <= 0 {
+		return dap
+	}
+
+	xt := math.Asin(math.Sin(dap/earthRadius) * math.Sin(theta))
+	at := math.Acos(math.Max(-1, math.Min(1, math.Cos(dap/earthRadius)/math.Cos(xt))))
+	if at*earthRadius >= haversine(a, b) {
+		return haversine(b, p)
+	}
+	return math.Abs(xt) * earthRadius
+}
+
+// boxDistance returns the distance in meters from p to the nearest point of box, or 0 if box contains p.
+// Within the longitude span of the box, the nearest point is on the same meridian.
+// Otherwise it is on the nearest meridian edge, which is a great-circle segment.
+func boxDistance(p Point, box Box) float64 {
+	if lngWithin(p.Lng, box.MinLng, box.MaxLng) {
+		switch {
+		case p.Lat < box.MinLat:
+			return (box.MinLat - p.Lat) * metersPerDegree
+		case p.Lat > box.MaxLat:
+			return (p.Lat - box.MaxLat) * metersPerDegree
+		default:
+			return 0
+		}
+	}
+
+	west := segmentDistance(p, Point{box.MinLat, box.MinLng}, Point{box.MaxLat, box.MinLng})
+	east := segmentDistance(p, Point{box.MinLat, box.MaxLng}, Point{box.MaxLat, box.MaxLng})
+	return math.Min(west, east)
+}
+
+// lngWithin reports whether lng is within [min, max], comparing longitudes modulo 360 degrees.
+func lngWithin(lng, min, max float64) bool {
+	d := math.Mod(lng-min, 2*lngMax)
+	if d < 0 {
+		d += 2 * lngMax
+	}
+	return d <= max-min
+}
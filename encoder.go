@@ -0,0 +1,235 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidBounds is returned by NewEncoder when the bounds are not finite or have no area.
+var ErrInvalidBounds = errors.New("geohash: invalid encoder bounds")
+
+// WebMercatorBounds are the bounds of the Web Mercator projection (EPSG:3857) in meters.
+// The lat fields hold northing (y) and the lng fields hold easting (x).
+var WebMercatorBounds = Box{
+	MinLat: -20037508.342789244, MaxLat: 20037508.342789244,
+	MinLng: -20037508.342789244, MaxLng: 20037508.342789244,
+}
+
+// WGS84 is an Encoder using the standard geohash bounds of ±90 latitude and ±180 longitude with longitude wrapping.
+// Its results match the package level functions for precision up to 12 characters.
+var WGS84 = &Encoder{
+	bounds: Box{MinLat: -latMax, MaxLat: latMax, MinLng: -lngMax, MaxLng: lngMax},
+	wrap:   true,
+}
+
+// Encoder applies the geohash interleaving and Z-order to coordinates within arbitrary axis bounds.
+// This allows the same machinery to index non-WGS84 planar data, such as a city-local grid, Web Mercator meters, or a game map.
+// The bounds are expressed as a Box, where the lat fields hold the vertical axis (y) and the lng fields hold the horizontal axis (x).
+// Coordinates outside the bounds are clamped to the edge cells.
+// The methods mirror the package level functions with the same names, and precision up to 12 characters (64 bits) is supported.
+type Encoder struct {
+	bounds Box
+	wrap   bool
+}
+
+// NewEncoder returns an Encoder for coordinates within bounds.
+// If wrap is true, the horizontal axis wraps like longitude at the antimeridian when finding neighbors.
+// ErrInvalidBounds is returned if a bound is not finite or a max bound is not greater than its min bound.
+func NewEncoder(bounds Box, wrap bool) (*Encoder, error) {
+	for _, v := range []float64{bounds.MinLat, bounds.MaxLat, bounds.MinLng, bounds.MaxLng} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("%w: %+v", ErrInvalidBounds, bounds)
+		}
+	}
+	if bounds.MaxLat <= bounds.MinLat || bounds.MaxLng <= bounds.MinLng {
+		return nil, fmt.Errorf("%w: %+v", ErrInvalidBounds, bounds)
+	}
+	return &Encoder{bounds: bounds, wrap: wrap}, nil
+}
+
+// Bounds returns the axis bounds of the Encoder.
+func (e *Encoder) Bounds() Box {
+	return e.bounds
+}
+
+// Encode returns a geohash string of the y, x coordinates based on the max character precision of 12.
+func (e *Encoder) Encode(y, x float64) string {
+	return e.EncodePrecision(y, x, precisionMax)
+}
+
+// EncodePrecision returns a geohash string of the y, x coordinates based on the provided character precision.
+// Acceptable precision values are 1 to 12 characters.
+func (e *Encoder) EncodePrecision(y, x float64, precision int) string {
+	precision = validate(precisionMin, precisionMax, precision)
+	hash := e.encodeInt(y, x, precision*5)
+	return encodeIntToStr(hash)[precisionMax-precision:]
+}
+
+// EncodeInt returns a uint64 geohash of y, x coordinates based on the max bit precision of 64.
+func (e *Encoder) EncodeInt(y, x float64) uint64 {
+	return e.encodeInt(y, x, bitsMax)
+}
+
+// EncodeIntPrecision returns a uint64 geohash of y, x coordinates based on the provided bit precision.
+// Acceptable bit values are 1 to 64.
+func (e *Encoder) EncodeIntPrecision(y, x float64, bits int) uint64 {
+	bits = validate(bitsMin, bitsMax, bits)
+	return e.encodeInt(y, x, bits)
+}
+
+// Decode returns the estimated y, x coordinates of a geohash string up to a precision of 12 characters.
+// Exceeding character limit will truncate the geohash string to the precision max of 12 characters.
+// ErrInvalidHash is returned if hash contains a character outside the base32 alphabet.
+func (e *Encoder) Decode(hash string) (float64, float64, error) {
+	h, err := parseTruncated(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, x := e.decodeInt(h.value, h.bits)
+	return y, x, nil
+}
+
+// DecodeInt returns the estimated y, x coordinates for a geohash integer.
+// Assumes max precision of 64 bits.
+func (e *Encoder) DecodeInt(hash uint64) (float64, float64) {
+	return e.decodeInt(hash, bitsMax)
+}
+
+// DecodeIntPrecision returns the estimated y, x coordinates for a geohash integer of specified precision.
+func (e *Encoder) DecodeIntPrecision(hash uint64, bits int) (float64, float64) {
+	return e.decodeInt(hash, bits)
+}
+
+// DecodeBox returns the bounding box of the cell described by a geohash string up to a precision of 12 characters.
+// Exceeding character limit will truncate the geohash string to the precision max of 12 characters.
+// ErrInvalidHash is returned if hash contains a character outside the base32 alphabet.
+func (e *Encoder) DecodeBox(hash string) (Box, error) {
+	h, err := parseTruncated(hash)
+	if err != nil {
+		return Box{}, err
+	}
+	if h.bits == 0 {
+		return e.bounds, nil
+	}
+	return e.DecodeIntBox(h.value, h.bits), nil
+}
+
+// DecodeIntBox returns the bounding box of the cell described by a geohash integer of specified precision.
+// The edges are computed from the cell row and column, so adjacent cells share identical edge values.
+func (e *Encoder) DecodeIntBox(hash uint64, bits int) Box {
+	bits = validate(bitsMin, bitsMax, bits)
+	latBits, lngBits := gridBits(bits)
+	lat, lng := gridCell(hash, bits)
+
+	b := Box{}
+	b.MinLat, b.MaxLat = e.edges(lat, latBits, e.bounds.MinLat, e.bounds.MaxLat)
+	b.MinLng, b.MaxLng = e.edges(lng, lngBits, e.bounds.MinLng, e.bounds.MaxLng)
+	return b
+}
+
+// Neighbor returns the cell adjacent to hash in direction d with the same precision.
+// No cell exists beyond the bounds; false is returned unless the horizontal axis wraps.
+// False is also returned for an empty or invalid geohash string and an invalid Direction.
+func (e *Encoder) Neighbor(hash string, d Direction) (string, bool) {
+	h, err := parseTruncated(hash)
+	if err != nil || h.bits == 0 || !d.valid() {
+		return "", false
+	}
+	step := directionSteps[d]
+	n, ok := e.neighbor(h.value, h.bits, step[0], step[1])
+	if !ok {
+		return "", false
+	}
+	return EncodeIntToStr(n, h.bits/5), true
+}
+
+// Neighbors returns the cells adjacent to hash in clockwise order starting north.
+// Neighbors beyond the bounds do not exist and are omitted. An empty or invalid geohash string has no neighbors.
+func (e *Encoder) Neighbors(hash string) []string {
+	h, err := parseTruncated(hash)
+	if err != nil || h.bits == 0 {
+		return nil
+	}
+
+	neighbors := make([]string, 0, len(directionSteps))
+	for _, step := range directionSteps {
+		if n, ok := e.neighbor(h.value, h.bits, step[0], step[1]); ok {
+			neighbors = append(neighbors, EncodeIntToStr(n, h.bits/5))
+		}
+	}
+	return neighbors
+}
+
+// encodeInt clamps y, x to the bounds, normalizes them as uint32 values, then interleaves a uint64 hash.
+// Returns a right shifted uint64 to achieve the desired bit precision.
+func (e *Encoder) encodeInt(y, x float64, bits int) uint64 {
+	lat32 := clampRange(y, e.bounds.MinLat, e.bounds.MaxLat)
+	lng32 := clampRange(x, e.bounds.MinLng, e.bounds.MaxLng)
+	hash := interleave(lat32, lng32)
+	return hash >> (64 - bits)
+}
+
+// decodeInt returns the estimated y, x coordinates by deinterleaving the uint64 to their respective uint32 values.
+// This is identical to the package level decodeInt using the bounds of the Encoder.
+func (e *Encoder) decodeInt(hash uint64, bits int) (float64, float64) {
+	lat32, lng32 := deinterleave(hash << (64 - bits))
+	y := decodeRange(lat32, e.bounds.MinLat, e.bounds.MaxLat)
+	x := decodeRange(lng32, e.bounds.MinLng, e.bounds.MaxLng)
+	return y, x
+}
+
+// edges returns the min and max edge of row or column i of the 2^n cells between min and max.
+// The max edge of the last cell is the max bound itself rather than a computed value.
+func (e *Encoder) edges(i uint32, n int, min, max float64) (float64, float64) {
+	cells := math.Exp2(float64(n))
+	lo := min + (max-min)*float64(i)/cells
+	if float64(i)+1 == cells {
+		return lo, max
+	}
+	return lo, min + (max-min)*(float64(i)+1)/cells
+}
+
+// neighbor returns the cell dLat rows and dLng columns away from a geohash integer of bit precision.
+// Moving past the first or last row or column returns false, unless the columns wrap.
+func (e *Encoder) neighbor(hash uint64, bits, dLat, dLng int) (uint64, bool) {
+	latBits, lngBits := gridBits(bits)
+	lat, lng := gridCell(hash, bits)
+
+	row := int64(lat) + int64(dLat)
+	col := int64(lng) + int64(dLng)
+	rows, cols := int64(1)<<latBits, int64(1)<<lngBits
+
+	if row < 0 || row >= rows {
+		return 0, false
+	}
+	if col < 0 || col >= cols {
+		if !e.wrap {
+			return 0, false
+		}
+		col = (col + cols) % cols
+	}
+
+	return gridHash(uint32(row), uint32(col), bits), true
+}
+
+// parseTruncated parses a geohash string using ParseHash after truncating it to the precision max of 12 characters.
+func parseTruncated(hash string) (Hash, error) {
+	if len(hash) > precisionMax {
+		hash = hash[:precisionMax]
+	}
+	return ParseHash(hash)
+}
+
+// clampRange returns encodeRange of x, clamping x to the first or last cell when outside or on the edge of min and max.
+// The max edge normalizes to 2^32, which cannot be represented as a uint32.
+func clampRange(x, min, max float64) uint32 {
+	switch {
+	case x >= max:
+		return math.MaxUint32
+	case x <= min:
+		return 0
+	default:
+		return encodeRange(x, min, max)
+	}
+}
@@ -0,0 +1,137 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEncoderWGS84(t *testing.T) {
+	for _, c := range testCases {
+		if res := WGS84.Encode(c.lat, c.lng); res != c.hash {
+			t.Errorf("Encode = %s, want %s", res, c.hash)
+		}
+		if res := WGS84.EncodeInt(c.lat, c.lng); res != c.hashInt {
+			t.Errorf("EncodeInt = %x, want %x", res, c.hashInt)
+		}
+
+		lat, lng, err := WGS84.Decode(c.hash)
+		wantLat, wantLng := Decode(c.hash)
+		if err != nil || lat != wantLat || lng != wantLng {
+			t.Errorf("Decode = %.6f, %.6f, want %.6f, %.6f", lat, lng, wantLat, wantLng)
+		}
+
+		box, err := WGS84.DecodeBox(c.hash)
+		if want := DecodeBox(c.hash); err != nil || box != want {
+			t.Errorf("DecodeBox = %+v, want %+v", box, want)
+		}
+	}
+
+	for _, c := range neighborsCases {
+		if res := WGS84.Neighbors(c.hash); !slices.Equal(res, c.neighbors) {
+			t.Errorf("Neighbors(%s) = %v, want %v", c.hash, res, c.neighbors)
+		}
+	}
+}
+
+func TestEncoderPlanar(t *testing.T) {
+	// A 1000m x 2000m local grid with the origin in the southwest corner.
+	e, err := NewEncoder(Box{MinLat: 0, MaxLat: 1000, MinLng: 0, MaxLng: 2000}, false)
+	if err != nil {
+		t.Fatalf("NewEncoder = %s", err.Error())
+	}
+
+	// The first character splits x then y, so the southwest quadrant begins with 0 and the northeast with z.
+	if res := e.EncodePrecision(1, 1, 1); res != "0" {
+		t.Errorf("EncodePrecision = %s, want 0", res)
+	}
+	if res := e.EncodePrecision(999, 1999, 1); res != "z" {
+		t.Errorf("EncodePrecision = %s, want z", res)
+	}
+
+	// Out of bounds coordinates are clamped to the edge cells.
+	if res := e.EncodePrecision(-5, 3000, 1); res != "p" {
+		t.Errorf("EncodePrecision = %s, want p", res)
+	}
+
+	y, x := 123.456, 1789.012
+	hash := e.Encode(y, x)
+	box, err := e.DecodeBox(hash)
+	if err != nil || !box.Contains(y, x) || box.Height() > 0.001 {
+		t.Errorf("DecodeBox = %+v, does not contain %f, %f", box, y, x)
+	}
+
+	resY, resX := e.DecodeInt(e.EncodeInt(y, x))
+	if math.Abs(resY-y) > 1e-6 || math.Abs(resX-x) > 1e-6 {
+		t.Errorf("DecodeInt = %f, %f, want %f, %f", resY, resX, y, x)
+	}
+
+	// Without wrapping, the east edge has no eastern neighbors.
+	if res := e.Neighbors("z"); !slices.Equal(res, []string{"x", "w", "y"}) {
+		t.Errorf("Neighbors = %v, want [x w y]", res)
+	}
+	if _, ok := e.Neighbor("p", East); ok {
+		t.Errorf("Neighbor = true, want false")
+	}
+}
+
+func TestEncoderWebMercator(t *testing.T) {
+	e, err := NewEncoder(WebMercatorBounds, true)
+	if err != nil {
+		t.Fatalf("NewEncoder = %s", err.Error())
+	}
+
+	// The quadrants of the first character match WGS84 as both projections share the origin and horizontal bounds.
+	if res := e.EncodePrecision(-1000, 1000, 1); res != "k" {
+		t.Errorf("EncodePrecision = %s, want k", res)
+	}
+	if res, _ := e.Neighbor("8", West); res != "x" {
+		t.Errorf("Neighbor = %s, want x", res)
+	}
+}
+
+func TestEncoderInvalid(t *testing.T) {
+	for _, hash := range []string{"dqcja", "dqc j", "DQCJQ"} {
+		if _, _, err := WGS84.Decode(hash); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("Decode(%q) = %v, want %v", hash, err, ErrInvalidHash)
+		}
+		if _, err := WGS84.DecodeBox(hash); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("DecodeBox(%q) = %v, want %v", hash, err, ErrInvalidHash)
+		}
+		if res, ok := WGS84.Neighbor(hash, North); ok {
+			t.Errorf("Neighbor(%q) = %s, true, want false", hash, res)
+		}
+		if res := WGS84.Neighbors(hash); res != nil {
+			t.Errorf("Neighbors(%q) = %v, want nil", hash, res)
+		}
+	}
+
+	if res, ok := WGS84.Neighbor("", North); ok {
+		t.Errorf("Neighbor(\"\") = %s, true, want false", res)
+	}
+	for _, d := range []Direction{-1, NorthWest + 1} {
+		if res, ok := WGS84.Neighbor("dqcjq", d); ok {
+			t.Errorf("Neighbor(%d) = %s, true, want false", d, res)
+		}
+	}
+
+	// Characters beyond the precision max of 12 are truncated before validation.
+	if _, _, err := WGS84.Decode("dqcjqcp84c6e!"); err != nil {
+		t.Errorf("Decode = %v, want nil", err)
+	}
+}
+
+func TestNewEncoder(t *testing.T) {
+	for _, b := range []Box{{}, {MinLat: 1, MaxLat: 0, MaxLng: 1}, {MaxLat: math.Inf(1), MaxLng: 1}, {MaxLat: math.NaN(), MaxLng: 1}} {
+		if _, err := NewEncoder(b, false); !errors.Is(err, ErrInvalidBounds) {
+			t.Errorf("NewEncoder(%+v) = %v, want %v", b, err, ErrInvalidBounds)
+		}
+	}
+}
+
+func BenchmarkEncoderEncode(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		WGS84.Encode(testLat, testLng)
+	}
+}
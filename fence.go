@@ -0,0 +1,137 @@
+package geohash
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Fence is a named geofence covered by geohash cells of mixed precision up to 12 characters.
+type Fence struct {
+	Name  string   `json:"name"`
+	Cells []string `json:"cells"`
+}
+
+// FenceIndex is an immutable lookup of the fences containing a point, compiled from fence coverings by NewFenceIndex.
+// The cells are stored in a trie on base32 characters, where each node lists the fences with a cell ending at that node.
+// A lookup walks at most 12 nodes using the 5-bit groups of EncodeInt, collecting fences along the way,
+// so its cost depends on the precision of the cells rather than the number of fences.
+// A FenceIndex is safe for concurrent use.
+type FenceIndex struct {
+	nodes []fenceNode
+	names []string
+}
+
+// fenceNode is a node of the FenceIndex trie. A child index of 0 is no child, as the root is never a child.
+type fenceNode struct {
+	children [32]uint32
+	fences   []uint32
+}
+
+// NewFenceIndex compiles fences into a FenceIndex.
+// The cells of each fence are normalized as a Set, so a fence is returned at most once per lookup.
+// Fences with the same name are returned separately. ErrInvalidHash is returned for invalid cells.
+func NewFenceIndex(fences []Fence) (*FenceIndex, error) {
+	x := &FenceIndex{nodes: make([]fenceNode, 1), names: make([]string, len(fences))}
+
+	for i, f := range fences {
+		x.names[i] = f.Name
+
+		set, err := NewSet(f.Cells...)
+		if err != nil {
+			return nil, fmt.Errorf("fence %q: %w", f.Name, err)
+		}
+
+		set.Range(func(hash string) bool {
+			node := uint32(0)
+			for j := 0; j < len(hash); j++ {
+				c := base32Lookup[hash[j]]
+				if x.nodes[node].children[c] == 0 {
+					x.nodes = append(x.nodes, fenceNode{})
+					x.nodes[node].children[c] = uint32(len(x.nodes) - 1)
+				}
+				node = x.nodes[node].children[c]
+			}
+			x.nodes[node].fences = append(x.nodes[node].fences, uint32(i))
+			return true
+		})
+	}
+
+	return x, nil
+}
+
+// Lookup returns the names of the fences containing the lat, lng coordinates, ordered from the coarsest matching cell.
+// Points on the max edges of 90 and 180 fall within the northernmost and easternmost cells.
+func (x *FenceIndex) Lookup(lat, lng float64) []string {
+	return x.AppendLookup(nil, lat, lng)
+}
+
+// AppendLookup appends the names of the fences containing the lat, lng coordinates to dst and returns the extended slice.
+// Reusing dst across lookups avoids allocating.
+func (x *FenceIndex) AppendLookup(dst []string, lat, lng float64) []string {
+	hash := encodeIntClamp(lat, lng, bitsMax)
+
+	node := uint32(0)
+	for i := 0; ; i++ {
+		for _, f := range x.nodes[node].fences {
+			dst = append(dst, x.names[f])
+		}
+		if i == precisionMax {
+			return dst
+		}
+
+		// The 5 bits of the next character, starting from the top of the 64-bit geohash integer.
+		c := hash >> (59 - 5*i) & 0x1f
+		if node = x.nodes[node].children[c]; node == 0 {
+			return dst
+		}
+	}
+}
+
+// Len returns the number of fences in the index.
+func (x *FenceIndex) Len() int {
+	return len(x.names)
+}
+
+// Geofencer serves lookups from a FenceIndex that can be replaced while lookups are running.
+// The index is held in an atomic pointer, so readers never block and each lookup sees either the old or the new index.
+// The zero value has no fences.
+type Geofencer struct {
+	index atomic.Pointer[FenceIndex]
+}
+
+// NewGeofencer returns a Geofencer serving lookups from index.
+func NewGeofencer(index *FenceIndex) *Geofencer {
+	g := &Geofencer{}
+	g.index.Store(index)
+	return g
+}
+
+// Index returns the FenceIndex currently serving lookups, or nil if none has been stored.
+func (g *Geofencer) Index() *FenceIndex {
+	return g.index.Load()
+}
+
+// Store replaces the FenceIndex serving lookups.
+func (g *Geofencer) Store(index *FenceIndex) {
+	g.index.Store(index)
+}
+
+// Reload compiles fences and replaces the FenceIndex serving lookups.
+// Compiling happens before the swap, so lookups continue using the previous index meanwhile and if an error is returned.
+func (g *Geofencer) Reload(fences []Fence) error {
+	index, err := NewFenceIndex(fences)
+	if err != nil {
+		return err
+	}
+	g.index.Store(index)
+	return nil
+}
+
+// Lookup returns the names of the fences containing the lat, lng coordinates using the current index.
+func (g *Geofencer) Lookup(lat, lng float64) []string {
+	index := g.index.Load()
+	if index == nil {
+		return nil
+	}
+	return index.Lookup(lat, lng)
+}
@@ -0,0 +1,131 @@
+package geohash
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+var testFences = []Fence{
+	{Name: "world", Cells: []string{""}},
+	{Name: "nyc", Cells: []string{"dr5r", "dr5x", "dr5rs"}},
+	{Name: "manhattan", Cells: []string{"dr5ru", "dr5rv", "dr5rs"}},
+	{Name: "london", Cells: []string{"gcpv"}},
+}
+
+func TestFenceIndex(t *testing.T) {
+	x, err := NewFenceIndex(testFences)
+	if err != nil {
+		t.Fatalf("NewFenceIndex = %s", err.Error())
+	}
+
+	testCases := []struct {
+		hash string
+		want []string
+	}{
+		{"dr5rsq", []string{"world", "nyc", "manhattan"}},
+		{"dr5ru", []string{"world", "nyc", "manhattan"}},
+		{"dr5rg", []string{"world", "nyc"}},
+		{"dr5xx", []string{"world", "nyc"}},
+		{"gcpvj0du", []string{"world", "london"}},
+		{"s0000", []string{"world"}},
+	}
+
+	for _, c := range testCases {
+		lat, lng := DecodeBox(c.hash).Center()
+		if res := x.Lookup(lat, lng); !slices.Equal(res, c.want) {
+			t.Errorf("Lookup(%s) = %v, want %v", c.hash, res, c.want)
+		}
+	}
+
+	if x.Len() != len(testFences) {
+		t.Errorf("Len = %d, want %d", x.Len(), len(testFences))
+	}
+
+	_, err = NewFenceIndex([]Fence{{Name: "bad", Cells: []string{"dqcja"}}})
+	if !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("NewFenceIndex = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestFenceIndexMaxEdge(t *testing.T) {
+	x, err := NewFenceIndex([]Fence{{Name: "north", Cells: []string{"u"}}, {Name: "northeast", Cells: []string{"z"}}})
+	if err != nil {
+		t.Fatalf("NewFenceIndex = %s", err.Error())
+	}
+
+	// Points on the max edges fall within the northernmost row and easternmost column rather than overflowing to the opposite edge.
+	testCases := []struct {
+		lat, lng float64
+		want     []string
+	}{
+		{90, 10, []string{"north"}},
+		{50, 180, []string{"northeast"}},
+		{90, 180, []string{"northeast"}},
+		{89.999, 179.999, []string{"northeast"}},
+	}
+	for _, c := range testCases {
+		if res := x.Lookup(c.lat, c.lng); !slices.Equal(res, c.want) {
+			t.Errorf("Lookup(%f, %f) = %v, want %v", c.lat, c.lng, res, c.want)
+		}
+	}
+}
+
+func TestGeofencer(t *testing.T) {
+	var g Geofencer
+	if res := g.Lookup(0, 0); res != nil {
+		t.Errorf("Lookup = %v, want nil", res)
+	}
+
+	if err := g.Reload(testFences[1:2]); err != nil {
+		t.Fatalf("Reload = %s", err.Error())
+	}
+	lat, lng := DecodeBox("dr5rsq").Center()
+
+	// Readers running during reloads always see a complete index.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 1000; n++ {
+				if res := g.Lookup(lat, lng); len(res) == 0 {
+					t.Errorf("Lookup = %v, want a fence", res)
+					return
+				}
+			}
+		}()
+	}
+	for n := 0; n < 100; n++ {
+		if err := g.Reload(testFences[n%2+1 : n%2+2]); err != nil {
+			t.Fatalf("Reload = %s", err.Error())
+		}
+	}
+	wg.Wait()
+
+	// A failed reload keeps the previous index.
+	index := g.Index()
+	if err := g.Reload([]Fence{{Name: "bad", Cells: []string{"a"}}}); err == nil {
+		t.Errorf("Reload = nil, want an error")
+	}
+	if g.Index() != index {
+		t.Errorf("Index was replaced by a failed reload")
+	}
+}
+
+func BenchmarkFenceLookup(b *testing.B) {
+	fences := make([]Fence, 0, 1000)
+	for i, hash := range CoverBox(benchRangesBox, 6) {
+		fences = append(fences, Fence{Name: hash, Cells: []string{hash}})
+		if i == cap(fences)-1 {
+			break
+		}
+	}
+	x, _ := NewFenceIndex(fences)
+	dst := make([]string, 0, 8)
+
+	for n := 0; n < b.N; n++ {
+		dst = x.AppendLookup(dst[:0], 40.75, -73.98)
+	}
+}
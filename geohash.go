@@ -7,6 +7,8 @@ package geohash
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"math"
 	"slices"
 	"strconv"
@@ -27,6 +29,21 @@ const (
 // bit positions for a 5-bit geohash character utilized by encodeBitwiseOr
 var bitPositions = []int{16, 8, 4, 2, 1}
 
+// base32Lookup maps a byte to its base32 index, or -1 if the byte is not a geohash character.
+var base32Lookup = func() [256]int8 {
+	t := [256]int8{}
+	for i := range t {
+		t[i] = -1
+	}
+	for i := 0; i < len(base32); i++ {
+		t[base32[i]] = int8(i)
+	}
+	return t
+}()
+
+// ErrInvalidHash is returned by functions that validate geohash strings when a character is outside the base32 alphabet.
+var ErrInvalidHash = errors.New("geohash: invalid geohash string")
+
 // Encode returns a geohash string of the lat, lng coordinates based on the max character precision of 12.
 func Encode(lat, lng float64) string {
 	return encode(lat, lng, precisionMax)
@@ -91,6 +108,12 @@ func DecodeHighPrecision(hash string) (float64, float64) {
 	return decodeBits(hash)
 }
 
+// DecodeBox returns the bounding box of the cell described by a geohash string up to a precision of 20 characters.
+// Exceeding character limit will truncate the geohash string to the precision max of 20 characters.
+func DecodeBox(hash string) Box {
+	return decodeBox(truncateHigh(hash))
+}
+
 // DecodeInt returns the estimated lat, lng coordinates for a geohash integer.
 // Assumes max precision of 64 bits.
 func DecodeInt(hash uint64) (float64, float64) {
@@ -113,25 +136,31 @@ func decode(hash string) (float64, float64) {
 // The uint32 values are decoded using decodeRange to return the latitude and longitude values.
 func decodeInt(hash uint64, bits int) (float64, float64) {
 	lat32, lng32 := deinterleave(hash << (64 - bits))
-	lat := decodeRange(lat32, latMax)
-	lng := decodeRange(lng32, lngMax)
+	lat := decodeRange(lat32, -latMax, latMax)
+	lng := decodeRange(lng32, -lngMax, lngMax)
 	return lat, lng
 }
 
-// decodeRange denormalizes x (uint32 of lat or lng) based on its range (90 or 180).
-func decodeRange(x uint32, r float64) float64 {
+// decodeRange denormalizes x (uint32 of lat or lng) based on its range (±90 or ±180 for standard geohashes).
+func decodeRange(x uint32, min, max float64) float64 {
 	p := float64(x) / math.Exp2(32)
-	return 2*r*p - r
+	return (max-min)*p + min
 }
 
 // decodeBits returns the estimated lat, lng coordinates for a geohash string of any precision.
+// The center of the box returned by decodeBox is the estimated point of the geohash string.
+func decodeBits(hash string) (float64, float64) {
+	return decodeBox(hash).Center()
+}
+
+// decodeBox returns the bounding box for a geohash string of any precision.
 // Each bit of every 5-bit character of the geohash string is evaluated.
 // Starting with bit 5, each character is shifted right by 4, decrementing to 0.
 // This moves each bit to the zero position in sequence.
 // A bitwise and operation is performed using this value and 1 to determine if the bit is 0 or 1.
 // Each iteration produces a box of min/max values for lat/lng respectively.
-// The center of this box is returned as the estimated point of the geohash string.
-func decodeBits(hash string) (float64, float64) {
+// The box produced by the final iteration is the cell described by the geohash string.
+func decodeBox(hash string) Box {
 	latmin, latmax := -latMax, latMax
 	lngmin, lngmax := -lngMax, lngMax
 	even := true
@@ -161,11 +190,7 @@ func decodeBits(hash string) (float64, float64) {
 		}
 	}
 
-	// Could return a bounding box here using sw: min values, ne: max values
-
-	lat := float64((latmin + latmax) / 2)
-	lng := float64((lngmin + lngmax) / 2)
-	return lat, lng
+	return Box{MinLat: latmin, MaxLat: latmax, MinLng: lngmin, MaxLng: lngmax}
 }
 
 // encode returns a geohash string of desired character precision based on provided lat, lng coordinates.
@@ -182,15 +207,26 @@ func encode(lat, lng float64, precision int) string {
 // encodeInt normalizes lat, lng coordinates as uint32 values then interleaves a uint64 hash.
 // Returns a right shifted uint64 to achieve the desired bit precision.
 func encodeInt(lat, lng float64, bits int) uint64 {
-	lat32 := encodeRange(lat, latMax)
-	lng32 := encodeRange(lng, lngMax)
+	lat32 := encodeRange(lat, -latMax, latMax)
+	lng32 := encodeRange(lng, -lngMax, lngMax)
 	hash := interleave(lat32, lng32)
 	return hash >> (64 - bits)
 }
 
-// encodeRange normalizes x (lat or lng) based on its range (90 or 180) into to [0,1] as a uint32.
-func encodeRange(x, r float64) uint32 {
-	return uint32(math.Floor(math.Exp2(32) * (x + r) / (r * 2)))
+// encodeIntClamp is encodeInt with lat, lng clamped to the first or last cell using clampRange.
+// The max edges of 90 and 180 otherwise normalize to 2^32, which overflows to the cell at the opposite corner of the world.
+// Lookups that must place points on the max edges in the northernmost row and easternmost column encode through this function.
+func encodeIntClamp(lat, lng float64, bits int) uint64 {
+	lat32 := clampRange(lat, -latMax, latMax)
+	lng32 := clampRange(lng, -lngMax, lngMax)
+	hash := interleave(lat32, lng32)
+	return hash >> (64 - bits)
+}
+
+// encodeRange normalizes x (lat or lng) based on its range (±90 or ±180 for standard geohashes) into to [0,1] as a uint32.
+// Other ranges allow the same interleaving to be applied to alternate coordinate limits, such as those used by Redis.
+func encodeRange(x, min, max float64) uint32 {
+	return uint32(math.Floor(math.Exp2(32) * (x - min) / (max - min)))
 }
 
 // encodeIntToStr returns a 12 character geohash string based on the provided uint64.
@@ -501,3 +537,24 @@ func validate(min, max, v int) int {
 		return v
 	}
 }
+
+// validHash returns ErrInvalidHash if hash is empty or contains a character outside the base32 alphabet.
+func validHash(hash string) error {
+	if hash == "" {
+		return fmt.Errorf("%w: empty hash", ErrInvalidHash)
+	}
+	for i := 0; i < len(hash); i++ {
+		if base32Lookup[hash[i]] < 0 {
+			return fmt.Errorf("%w: %q at index %d", ErrInvalidHash, hash[i], i)
+		}
+	}
+	return nil
+}
+
+// truncateHigh is a helper function that truncates a geohash string to the precision max of 20 characters.
+func truncateHigh(hash string) string {
+	if len(hash) > precisionHigh {
+		return hash[:precisionHigh]
+	}
+	return hash
+}
@@ -154,6 +154,22 @@ func TestDecodeInt(t *testing.T) {
 	}
 }
 
+func TestDecodeBox(t *testing.T) {
+	for _, c := range testCases {
+		box := DecodeBox(c.hashHighPrec)
+
+		if !box.Contains(c.lat, c.lng) {
+			t.Errorf("DecodeBox = %+v, does not contain %.9f, %.9f", box, c.lat, c.lng)
+		}
+
+		lat, lng := box.Center()
+		wantLat, wantLng := DecodeHighPrecision(c.hashHighPrec)
+		if lat != wantLat || lng != wantLng {
+			t.Errorf("Center = %.9f, %.9f, want %.9f, %.9f", lat, lng, wantLat, wantLng)
+		}
+	}
+}
+
 func BenchmarkEncodeStrConcat(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		encodeStrConcat(testLat, testLng, testPrecision)
@@ -0,0 +1,181 @@
+package geohash
+
+import "math"
+
+// The grid functions treat a geohash integer of a given bit precision as a cell in a grid of rows (lat) and columns (lng).
+// The row and column of a cell are the deinterleaved lat and lng bits of the hash.
+// This allows cells to be enumerated, compared, and stepped through with integer arithmetic rather than floating point.
+
+// gridBits splits a bit precision into the number of lat and lng bits.
+// Longitude is the first interleaved bit, so it receives the extra bit of an odd precision.
+func gridBits(bits int) (int, int) {
+	return bits / 2, bits - bits/2
+}
+
+// gridHash returns the geohash integer of the cell at row lat and column lng for a bit precision.
+// The row and column are moved to the high bits of their uint32 values as though they were produced by encodeRange.
+func gridHash(lat, lng uint32, bits int) uint64 {
+	latBits, lngBits := gridBits(bits)
+	return interleave(lat<<(32-latBits), lng<<(32-lngBits)) >> (64 - bits)
+}
+
+// gridCell returns the row and column of a geohash integer of a bit precision.
+func gridCell(hash uint64, bits int) (uint32, uint32) {
+	latBits, lngBits := gridBits(bits)
+	lat, lng := deinterleave(hash << (64 - bits))
+	return lat >> (32 - latBits), lng >> (32 - lngBits)
+}
+
+// gridSpan returns the first and last row or column of the 2^n cells within ±r that overlap [lo, hi].
+// A hi value on a cell edge does not include the following cell, matching the exclusive max edges of Box.
+// Values outside ±r are clamped to the first or last cell.
+func gridSpan(lo, hi, r float64, n int) (uint32, uint32) {
+	cells := math.Exp2(float64(n))
+	first := math.Floor((lo + r) / (2 * r) * cells)
+	last := math.Ceil((hi+r)/(2*r)*cells) - 1
+	first = math.Max(0, math.Min(first, cells-1))
+	last = math.Max(first, math.Min(last, cells-1))
+	return uint32(first), uint32(last)
+}
+
+// boxCells calls fn with the geohash integer of every cell of a bit precision that overlaps box.
+// Cells are visited row by row starting in the southwest corner. Iteration stops when fn returns false.
+// The box is assumed not to cross the antimeridian (MinLng <= MaxLng).
+func boxCells(box Box, bits int, fn func(uint64) bool) {
+	latBits, lngBits := gridBits(bits)
+	lat0, lat1 := gridSpan(box.MinLat, box.MaxLat, latMax, latBits)
+	lng0, lng1 := gridSpan(box.MinLng, box.MaxLng, lngMax, lngBits)
+
+	// uint64 counters avoid overflowing the loop condition when the last row or column is math.MaxUint32.
+	for lat := uint64(lat0); lat <= uint64(lat1); lat++ {
+		for lng := uint64(lng0); lng <= uint64(lng1); lng++ {
+			if !fn(gridHash(uint32(lat), uint32(lng), bits)) {
+				return
+			}
+		}
+	}
+}
+
+// boxBits returns the highest bit precision, up to bits, at which no more than maxCells cells overlap box.
+// Each bit removed halves the cells along one axis, so the loop runs at most bits times.
+func boxBits(box Box, bits, maxCells int) int {
+	for ; bits > bitsMin; bits-- {
+		latBits, lngBits := gridBits(bits)
+		lat0, lat1 := gridSpan(box.MinLat, box.MaxLat, latMax, latBits)
+		lng0, lng1 := gridSpan(box.MinLng, box.MaxLng, lngMax, lngBits)
+
+		// float64 avoids overflowing when a box spans all 2^32 rows or columns.
+		if (float64(lat1-lat0)+1)*(float64(lng1-lng0)+1) <= float64(maxCells) {
+			break
+		}
+	}
+	return bits
+}
+
+// gridOffset returns the signed number of rows and columns from cell h0 to cell h1 of a bit precision.
+// Columns wrap across the antimeridian, so the column offset is the shorter of the two directions, between -cols/2 and cols/2.
+func gridOffset(h0, h1 uint64, bits int) (int64, int64) {
+	_, lngBits := gridBits(bits)
+	lat0, lng0 := gridCell(h0, bits)
+	lat1, lng1 := gridCell(h1, bits)
+
+	cols := int64(1) << lngBits
+	dLat := int64(lat1) - int64(lat0)
+	dLng := int64(lng1) - int64(lng0)
+	if dLng > cols/2 {
+		dLng -= cols
+	} else if dLng < -cols/2 {
+		dLng += cols
+	}
+	return dLat, dLng
+}
+
+// abs returns the absolute value of x.
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// gridRing calls fn with the geohash integer of every cell exactly k rows or columns (Chebyshev distance) from a cell.
+// Cells are visited clockwise starting with the cell k rows north, so the first ring matches the order of Neighbors.
+// Columns wrap across the antimeridian and rows beyond the poles are omitted.
+// Once k exceeds half the columns, the columns k east and west wrap to cells nearer than k, so only the rows k north and south remain.
+// Once k also exceeds the last row, no cell is k away and nothing is visited. See gridRingMax.
+// Cells in seen are skipped and visited cells are added to it, if it is not nil. Use ringSeen to create it when needed.
+// Iteration stops and false is returned when fn returns false.
+func gridRing(hash uint64, bits, k int, seen map[uint64]bool, fn func(uint64) bool) bool {
+	if k == 0 {
+		return visitCell(hash, seen, fn)
+	}
+	if k > gridRingMax(bits) {
+		return true
+	}
+
+	latBits, lngBits := gridBits(bits)
+	lat, lng := gridCell(hash, bits)
+	rows, cols := int64(1)<<latBits, int64(1)<<lngBits
+	sides := int64(k) <= cols/2
+
+	// Walk the 8k cells of the perimeter: east along the top, south, west along the bottom, north, then east back to the start.
+	// The positions of each leg on rows beyond the poles are skipped arithmetically, rather than stepped over one at a time.
+	// Without sides, only the first position of the south and north legs, the corner on the top or bottom row, is visited.
+	row, col := int64(lat)+int64(k), int64(lng)
+	legs := [5][3]int64{{0, 1, int64(k)}, {-1, 0, 2 * int64(k)}, {0, -1, 2 * int64(k)}, {1, 0, 2 * int64(k)}, {0, 1, int64(k)}}
+	for _, leg := range legs {
+		dRow, dCol, n := leg[0], leg[1], leg[2]
+		first, last := int64(0), n-1
+		switch {
+		case dRow == 0 && (row < 0 || row >= rows):
+			last = -1
+		case dRow < 0:
+			first, last = max(first, row-(rows-1)), min(last, row)
+		case dRow > 0:
+			first, last = max(first, -row), min(last, rows-1-row)
+		}
+		if dRow != 0 && !sides {
+			last = min(last, 0)
+		}
+
+		for i := first; i <= last; i++ {
+			c := col + dCol*i
+			h := gridHash(uint32(row+dRow*i), uint32(((c%cols)+cols)%cols), bits)
+			if !visitCell(h, seen, fn) {
+				return false
+			}
+		}
+		row += dRow * n
+		col += dCol * n
+	}
+	return true
+}
+
+// gridRingMax returns the largest k for which a ring of a bit precision can contain cells:
+// the last row, or half the columns if that is more. Every cell is within this many rows or columns of every other cell.
+func gridRingMax(bits int) int {
+	latBits, lngBits := gridBits(bits)
+	return int(max(int64(1)<<latBits-1, int64(1)<<lngBits/2))
+}
+
+// ringSeen returns a map to track the cells visited by gridRing when rings up to k are wider than the grid,
+// as the columns of a ring wider than the grid repeat. Otherwise every cell of a ring is distinct and nil is returned.
+func ringSeen(bits, k int) map[uint64]bool {
+	_, lngBits := gridBits(bits)
+	if int64(2*k+1) > int64(1)<<lngBits {
+		return map[uint64]bool{}
+	}
+	return nil
+}
+
+// visitCell calls fn with hash unless it is in seen, adding it to seen if seen is not nil.
+// Returns false when fn returns false.
+func visitCell(hash uint64, seen map[uint64]bool, fn func(uint64) bool) bool {
+	if seen != nil {
+		if seen[hash] {
+			return true
+		}
+		seen[hash] = true
+	}
+	return fn(hash)
+}
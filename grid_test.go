@@ -0,0 +1,41 @@
+package geohash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGridCell(t *testing.T) {
+	for _, c := range testCases {
+		for _, bits := range []int{1, 5, 25, 33, 60, 64} {
+			hash := EncodeIntPrecision(c.lat, c.lng, bits)
+			lat, lng := gridCell(hash, bits)
+
+			if res := gridHash(lat, lng, bits); res != hash {
+				t.Errorf("gridHash = %x, want %x", res, hash)
+			}
+		}
+	}
+}
+
+func TestBoxCells(t *testing.T) {
+	res := []string{}
+	boxCells(DecodeBox("dqc"), 25, func(hash uint64) bool {
+		res = append(res, EncodeIntToStr(hash, 5))
+		return true
+	})
+
+	if len(res) != 1024 {
+		t.Fatalf("boxCells = %d cells, want 1024", len(res))
+	}
+	for _, hash := range res {
+		if !strings.HasPrefix(hash, "dqc") {
+			t.Errorf("boxCells = %s, want prefix dqc", hash)
+		}
+	}
+
+	// Rows are visited from the south, so the first cell is the southwest corner.
+	if res[0] != "dqc00" || res[len(res)-1] != "dqczz" {
+		t.Errorf("boxCells = %s...%s, want dqc00...dqczz", res[0], res[len(res)-1])
+	}
+}
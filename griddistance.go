@@ -0,0 +1,105 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrPrecisionMismatch is returned by GridDistance and GridLine for cells of different precision.
+var ErrPrecisionMismatch = errors.New("geohash: cells of different precision")
+
+// GridDistance returns the number of cell steps between two geohash strings of the same precision,
+// as the Chebyshev distance (steps including diagonals, as counted by Ring) and the Manhattan distance (steps along edges only).
+// The row and column of each cell are deinterleaved from its geohash integer, so no trigonometry is involved.
+// Columns wrap across the antimeridian, so the shorter direction is counted.
+// Cells are not square and shrink in width toward the poles, so steps are only an approximate ranking of distance.
+// ErrInvalidHash is returned for invalid geohash strings, and ErrPrecisionMismatch for strings of different lengths.
+func GridDistance(a, b string) (chebyshev, manhattan int, err error) {
+	ha, hb, err := gridPair(a, b)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dLat, dLng := gridOffset(ha.value, hb.value, ha.bits)
+	dLat, dLng = abs(dLat), abs(dLng)
+	return int(max(dLat, dLng)), int(dLat + dLng), nil
+}
+
+// GridLine returns an iterator over the cells on a straight grid path from a to b, including both, for geohash strings of the same precision.
+// The path is the Bresenham line between the rows and columns of the cells, so consecutive cells share an edge or corner
+// and the number of cells is the Chebyshev distance plus one. Columns wrap across the antimeridian in the shorter direction.
+// Distant cells of high precision are billions of steps apart, so cells are generated as they are yielded rather than collected.
+// ErrInvalidHash is returned for invalid geohash strings, and ErrPrecisionMismatch for strings of different lengths.
+func GridLine(a, b string) (iter.Seq[string], error) {
+	ha, hb, err := gridPair(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(string) bool) {
+		if ha.bits == 0 {
+			yield(a)
+			return
+		}
+
+		_, lngBits := gridBits(ha.bits)
+		cols := int64(1) << lngBits
+		lat0, lng0 := gridCell(ha.value, ha.bits)
+		dLat, dLng := gridOffset(ha.value, hb.value, ha.bits)
+
+		// Step from the origin toward the offset, moving diagonally while the error term allows.
+		dx, dy := abs(dLng), abs(dLat)
+		sx, sy := sign(dLng), sign(dLat)
+		e := dx - dy
+
+		var x, y int64
+		for {
+			col := ((int64(lng0)+x)%cols + cols) % cols
+			if !yield(EncodeIntToStr(gridHash(uint32(int64(lat0)+y), uint32(col), ha.bits), len(a))) {
+				return
+			}
+			if x == dLng && y == dLat {
+				return
+			}
+
+			e2 := 2 * e
+			if e2 > -dy {
+				e -= dy
+				x += sx
+			}
+			if e2 < dx {
+				e += dx
+				y += sy
+			}
+		}
+	}, nil
+}
+
+// gridPair parses two geohash strings of the same precision.
+func gridPair(a, b string) (Hash, Hash, error) {
+	ha, err := ParseHash(a)
+	if err != nil {
+		return Hash{}, Hash{}, err
+	}
+	hb, err := ParseHash(b)
+	if err != nil {
+		return Hash{}, Hash{}, err
+	}
+	if ha.bits != hb.bits {
+		return Hash{}, Hash{}, fmt.Errorf("%w: %q and %q", ErrPrecisionMismatch, a, b)
+	}
+	return ha, hb, nil
+}
+
+// sign returns -1, 0 or 1 for negative, zero or positive x.
+func sign(x int64) int64 {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
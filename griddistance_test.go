@@ -0,0 +1,115 @@
+package geohash
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGridDistance(t *testing.T) {
+	testCases := []struct {
+		a, b                 string
+		chebyshev, manhattan int
+	}{
+		{"dqcjq", "dqcjq", 0, 0},
+		{"dqcjq", "dqcjw", 1, 1},
+		{"dqcjq", "dqcjx", 1, 2},
+		// Precision 1 has 8 columns, so 7 columns east is 1 column west across the antimeridian.
+		{"0", "p", 1, 1},
+		{"8", "x", 1, 1},
+		{"0", "z", 3, 4},
+	}
+
+	for _, c := range testCases {
+		cheb, manh, err := GridDistance(c.a, c.b)
+		if err != nil {
+			t.Fatalf("GridDistance = %s", err.Error())
+		}
+		if cheb != c.chebyshev || manh != c.manhattan {
+			t.Errorf("GridDistance(%s, %s) = %d, %d, want %d, %d", c.a, c.b, cheb, manh, c.chebyshev, c.manhattan)
+		}
+	}
+
+	// Every cell of a ring is at the Chebyshev distance of the ring.
+	for k := 0; k <= 3; k++ {
+		for hash := range Ring("dqcjq", k) {
+			if cheb, _, _ := GridDistance("dqcjq", hash); cheb != k {
+				t.Errorf("GridDistance(dqcjq, %s) = %d, want %d", hash, cheb, k)
+			}
+		}
+	}
+
+	if _, _, err := GridDistance("dqcjq", "dqcj"); !errors.Is(err, ErrPrecisionMismatch) {
+		t.Errorf("GridDistance = %v, want %v", err, ErrPrecisionMismatch)
+	}
+	if _, _, err := GridDistance("dqcja", "dqcjq"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("GridDistance = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestGridLine(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want []string
+	}{
+		{"dqcjq", "dqcjq", []string{"dqcjq"}},
+		{"dqcjn", "dqcjw", []string{"dqcjn", "dqcjq", "dqcjw"}},
+		{"8", "x", []string{"8", "x"}},
+	}
+
+	for _, c := range testCases {
+		seq, err := GridLine(c.a, c.b)
+		if err != nil {
+			t.Fatalf("GridLine = %s", err.Error())
+		}
+		if res := slices.Collect(seq); !slices.Equal(res, c.want) {
+			t.Errorf("GridLine(%s, %s) = %v, want %v", c.a, c.b, res, c.want)
+		}
+	}
+
+	// Consecutive cells are neighbors and the line has the Chebyshev distance plus one cells.
+	a, b := EncodePrecision(40.7, -74.0, 6), EncodePrecision(40.9, -73.5, 6)
+	seq, _ := GridLine(a, b)
+	line := slices.Collect(seq)
+	cheb, _, _ := GridDistance(a, b)
+	if len(line) != cheb+1 || line[0] != a || line[len(line)-1] != b {
+		t.Errorf("GridLine = %d cells from %s to %s, want %d from %s to %s", len(line), line[0], line[len(line)-1], cheb+1, a, b)
+	}
+	for i := 1; i < len(line); i++ {
+		if d, _, _ := GridDistance(line[i-1], line[i]); d != 1 {
+			t.Errorf("GridLine cells %s and %s are not neighbors", line[i-1], line[i])
+		}
+	}
+
+	if _, err := GridLine("dqcjq", "dqcj"); !errors.Is(err, ErrPrecisionMismatch) {
+		t.Errorf("GridLine = %v, want %v", err, ErrPrecisionMismatch)
+	}
+}
+
+func TestGridLineDistant(t *testing.T) {
+	// Opposite corners at 12 characters are about 2^29 steps apart; stopping early does not generate the rest of the line.
+	seq, err := GridLine("000000000000", "zzzzzzzzzzzz")
+	if err != nil {
+		t.Fatalf("GridLine = %s", err.Error())
+	}
+
+	n := 0
+	for hash := range seq {
+		if n == 0 && hash != "000000000000" {
+			t.Errorf("GridLine = %s first, want 000000000000", hash)
+		}
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("GridLine = %d cells, want to stop at 3", n)
+	}
+}
+
+func BenchmarkGridDistance(b *testing.B) {
+	x, y := EncodePrecision(40.7, -74.0, 9), EncodePrecision(40.9, -73.5, 9)
+	for n := 0; n < b.N; n++ {
+		GridDistance(x, y)
+	}
+}
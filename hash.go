@@ -0,0 +1,218 @@
+package geohash
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrHashBits is returned when a Hash whose bit precision is not a multiple of 5 is marshaled as a geohash string.
+var ErrHashBits = errors.New("geohash: bit precision is not a multiple of 5")
+
+// Hash is a geohash integer and its bit precision.
+// A Hash marshals to its geohash string for text and JSON, and to a compact varint form for binary encodings such as gob.
+// Unmarshaling validates the input, returning ErrInvalidHash rather than silently producing a malformed hash as EncodeStrToInt does.
+// The zero value is an empty hash with 0 bits of precision.
+type Hash struct {
+	value uint64
+	bits  int
+}
+
+// NewHash returns the Hash of lat, lng coordinates based on the provided bit precision.
+// Acceptable bit values are 1 to 64. Use a multiple of 5 if the Hash will be marshaled as text.
+func NewHash(lat, lng float64, bits int) Hash {
+	bits = validate(bitsMin, bitsMax, bits)
+	return Hash{value: encodeInt(lat, lng, bits), bits: bits}
+}
+
+// NewHashInt returns a Hash for a geohash integer of the provided bit precision, such as one returned by EncodeIntPrecision.
+// Acceptable bit values are 1 to 64. Any bits of value above the precision are discarded.
+func NewHashInt(value uint64, bits int) Hash {
+	bits = validate(bitsMin, bitsMax, bits)
+	return Hash{value: value & (math.MaxUint64 >> (64 - bits)), bits: bits}
+}
+
+// ParseHash returns the Hash of a geohash string of 1 to 12 characters.
+// Characters outside the base32 alphabet, or strings exceeding 12 characters, return ErrInvalidHash.
+func ParseHash(s string) (Hash, error) {
+	if len(s) > precisionMax {
+		return Hash{}, fmt.Errorf("%w: %q exceeds %d characters", ErrInvalidHash, s, precisionMax)
+	}
+
+	var value uint64
+	for i := 0; i < len(s); i++ {
+		idx := base32Lookup[s[i]]
+		if idx < 0 {
+			return Hash{}, fmt.Errorf("%w: %q at index %d", ErrInvalidHash, s[i], i)
+		}
+		value = value<<5 | uint64(idx)
+	}
+
+	return Hash{value: value, bits: len(s) * 5}, nil
+}
+
+// Int returns the geohash integer. The value is right aligned, matching EncodeIntPrecision with Bits.
+func (h Hash) Int() uint64 {
+	return h.value
+}
+
+// Bits returns the bit precision of the hash.
+func (h Hash) Bits() int {
+	return h.bits
+}
+
+// String returns the geohash string of the hash.
+// If the bit precision is not a multiple of 5, the trailing bits that do not form a whole character are omitted.
+func (h Hash) String() string {
+	precision := h.bits / 5
+	if precision == 0 {
+		return ""
+	}
+	return EncodeIntToStr(h.value>>(h.bits%5), precision)
+}
+
+// Decode returns the estimated lat, lng coordinates of the hash.
+func (h Hash) Decode() (float64, float64) {
+	if h.bits == 0 {
+		return 0, 0
+	}
+	return decodeInt(h.value, h.bits)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the geohash string.
+// ErrHashBits is returned if the bit precision is not a multiple of 5.
+func (h Hash) MarshalText() ([]byte, error) {
+	if h.bits%5 != 0 {
+		return nil, fmt.Errorf("%w: %d bits", ErrHashBits, h.bits)
+	}
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a geohash string using ParseHash.
+func (h *Hash) UnmarshalText(text []byte) error {
+	res, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = res
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The hash is encoded as the bit precision followed by the geohash integer, both as unsigned varints.
+// This is at most 11 bytes and does not require the bit precision to be a multiple of 5.
+func (h Hash) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+binary.MaxVarintLen64)
+	b = binary.AppendUvarint(b, uint64(h.bits))
+	b = binary.AppendUvarint(b, h.value)
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for data produced by MarshalBinary.
+// ErrInvalidHash is returned for truncated data, trailing data, or a value that exceeds its bit precision.
+func (h *Hash) UnmarshalBinary(data []byte) error {
+	bits, n := binary.Uvarint(data)
+	if n <= 0 || bits > bitsMax {
+		return fmt.Errorf("%w: invalid bit precision", ErrInvalidHash)
+	}
+	value, m := binary.Uvarint(data[n:])
+	if m <= 0 || n+m != len(data) {
+		return fmt.Errorf("%w: invalid binary value", ErrInvalidHash)
+	}
+	if bits < bitsMax && value>>bits != 0 {
+		return fmt.Errorf("%w: value exceeds %d bits", ErrInvalidHash, bits)
+	}
+
+	*h = Hash{value: value, bits: int(bits)}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, returning the geohash string as a JSON string.
+// Convert to IntHash to marshal the geohash integer instead.
+func (h Hash) MarshalJSON() ([]byte, error) {
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Both the string form of Hash and the integer form of IntHash are accepted.
+// Integers are read using the EncodeInt layout of 64 bits. Use HashJSON to unmarshal integers of another bit precision.
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(h, data, bitsMax)
+}
+
+// IntHash is a Hash that marshals to JSON as the geohash integer rather than the geohash string.
+// A JSON integer does not carry a bit precision, so the integer is always read using the EncodeInt layout of 64 bits.
+// Use HashJSON to unmarshal integers of another bit precision.
+type IntHash Hash
+
+// MarshalJSON implements json.Marshaler, returning the geohash integer as a JSON number.
+func (h IntHash) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, h.value, 10), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Both the integer form of IntHash and the string form of Hash are accepted.
+func (h *IntHash) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON((*Hash)(h), data, bitsMax)
+}
+
+// HashJSON is a json.Unmarshaler reading a geohash integer of a fixed bit precision into Hash.
+// The precision belongs to the field rather than the decoded value,
+// so reusing a HashJSON gives the same result regardless of the values decoded before.
+// A Bits of 0 assumes the EncodeInt layout of 64 bits. Geohash strings are decoded as by Hash.UnmarshalJSON.
+// HashJSON marshals to the geohash integer, as IntHash does.
+//
+// Example: v := struct{ Cell HashJSON }{Cell: HashJSON{Bits: 60}}; json.Unmarshal(data, &v)
+type HashJSON struct {
+	Hash Hash
+	Bits int
+}
+
+// MarshalJSON implements json.Marshaler, returning the geohash integer as a JSON number.
+func (c HashJSON) MarshalJSON() ([]byte, error) {
+	return IntHash(c.Hash).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *HashJSON) UnmarshalJSON(data []byte) error {
+	bits := c.Bits
+	if bits == 0 {
+		bits = bitsMax
+	}
+	return unmarshalJSON(&c.Hash, data, validate(bitsMin, bitsMax, bits))
+}
+
+// unmarshalJSON parses a JSON string using ParseHash or a JSON integer at the provided bit precision.
+// A JSON null leaves h unchanged, following the convention of encoding/json.
+func unmarshalJSON(h *Hash, data []byte, bits int) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return h.UnmarshalText([]byte(s))
+	}
+
+	value, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidHash, data)
+	}
+
+	if bits < bitsMax && value>>bits != 0 {
+		return fmt.Errorf("%w: value exceeds %d bits", ErrInvalidHash, bits)
+	}
+
+	*h = Hash{value: value, bits: bits}
+	return nil
+}
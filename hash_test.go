@@ -0,0 +1,184 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseHash(t *testing.T) {
+	for _, c := range testCases {
+		res, err := ParseHash(c.hash)
+		if err != nil {
+			t.Fatalf("ParseHash = %s", err.Error())
+		}
+
+		if res.Int() != c.hashInt>>4 || res.Bits() != 60 || res.String() != c.hash {
+			t.Errorf("ParseHash = %x, %d, %s, want %x, 60, %s", res.Int(), res.Bits(), res, c.hashInt>>4, c.hash)
+		}
+	}
+
+	for _, s := range []string{"dnga", "DNGB", "dngb2x6mnetr3", "dn b"} {
+		if _, err := ParseHash(s); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("ParseHash(%q) = %v, want %v", s, err, ErrInvalidHash)
+		}
+	}
+}
+
+func TestNewHash(t *testing.T) {
+	for _, c := range testCases {
+		res := NewHash(c.lat, c.lng, testBits)
+
+		if res.Int() != c.hashInt {
+			t.Errorf("NewHash = %x, want %x", res.Int(), c.hashInt)
+		}
+
+		lat, lng := res.Decode()
+		wantLat, wantLng := DecodeInt(c.hashInt)
+		if lat != wantLat || lng != wantLng {
+			t.Errorf("Decode = %.6f, %.6f, want %.6f, %.6f", lat, lng, wantLat, wantLng)
+		}
+	}
+
+	if res := NewHashInt(0xffff, 8); res.Int() != 0xff {
+		t.Errorf("NewHashInt = %x, want ff", res.Int())
+	}
+}
+
+func TestHashText(t *testing.T) {
+	h := NewHashInt(testHashInt>>4, 60)
+
+	b, err := h.MarshalText()
+	if err != nil || string(b) != testHash {
+		t.Errorf("MarshalText = %s, %v, want %s", b, err, testHash)
+	}
+
+	res := Hash{}
+	if err := res.UnmarshalText(b); err != nil || res != h {
+		t.Errorf("UnmarshalText = %v, %v, want %v", res, err, h)
+	}
+
+	if _, err := NewHashInt(testHashInt, testBits).MarshalText(); !errors.Is(err, ErrHashBits) {
+		t.Errorf("MarshalText = %v, want %v", err, ErrHashBits)
+	}
+}
+
+func TestHashBinary(t *testing.T) {
+	for _, c := range testCases {
+		for _, bits := range []int{1, 33, 60, 64} {
+			h := NewHash(c.lat, c.lng, bits)
+
+			b, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary = %s", err.Error())
+			}
+
+			res := Hash{}
+			if err := res.UnmarshalBinary(b); err != nil || res != h {
+				t.Errorf("UnmarshalBinary = %v, %v, want %v", res, err, h)
+			}
+		}
+	}
+
+	for _, b := range [][]byte{{}, {65, 0}, {8, 0x80, 0x02}, {8, 1, 0}, {8, 0x80}} {
+		res := Hash{}
+		if err := res.UnmarshalBinary(b); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("UnmarshalBinary(%v) = %v, want %v", b, err, ErrInvalidHash)
+		}
+	}
+}
+
+func TestHashGob(t *testing.T) {
+	h := NewHash(testLat, testLng, 37)
+	buf := bytes.Buffer{}
+
+	if err := gob.NewEncoder(&buf).Encode(h); err != nil {
+		t.Fatalf("Encode = %s", err.Error())
+	}
+
+	res := Hash{}
+	if err := gob.NewDecoder(&buf).Decode(&res); err != nil || res != h {
+		t.Errorf("Decode = %v, %v, want %v", res, err, h)
+	}
+}
+
+func TestHashJSON(t *testing.T) {
+	type cell struct {
+		Hash Hash    `json:"hash"`
+		Int  IntHash `json:"int"`
+	}
+
+	h := NewHashInt(testHashInt, testBits)
+	v := cell{Hash: NewHashInt(testHashInt>>4, 60), Int: IntHash(h)}
+
+	b, err := json.Marshal(v)
+	want := `{"hash":"dngb2x6mnetr","int":7286438770271023985}`
+	if err != nil || string(b) != want {
+		t.Fatalf("Marshal = %s, %v, want %s", b, err, want)
+	}
+
+	res := cell{}
+	if err := json.Unmarshal(b, &res); err != nil || res != v {
+		t.Errorf("Unmarshal = %v, %v, want %v", res, err, v)
+	}
+
+	// Integers are read at 64 bits, while strings carry their own precision.
+	res = cell{Hash: NewHashInt(0, 40), Int: IntHash(NewHashInt(0, 60))}
+	if err := json.Unmarshal([]byte(`{"hash":1234,"int":"dngb"}`), &res); err != nil {
+		t.Fatalf("Unmarshal = %s", err.Error())
+	}
+	if res.Hash.Bits() != 64 || res.Hash.Int() != 1234 || Hash(res.Int).String() != "dngb" {
+		t.Errorf("Unmarshal = %v, want 1234 (64 bits), dngb", res)
+	}
+
+	for _, s := range []string{`{"hash":"dnga"}`, `{"hash":-1}`, `{"hash":1.5}`} {
+		if err := json.Unmarshal([]byte(s), &res); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", s, err, ErrInvalidHash)
+		}
+	}
+}
+
+func TestHashJSONReuse(t *testing.T) {
+	// The precision of a previously decoded string does not carry over to a later integer.
+	var h IntHash
+	for _, c := range []struct {
+		data string
+		want Hash
+	}{
+		{`"dngb"`, NewHashInt(EncodeStrToInt("dngb"), 20)},
+		{`1234`, NewHashInt(1234, 64)},
+	} {
+		if err := json.Unmarshal([]byte(c.data), &h); err != nil || Hash(h) != c.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", c.data, h, err, c.want)
+		}
+	}
+
+	col := HashJSON{Bits: 20}
+	for _, c := range []struct {
+		data string
+		want Hash
+	}{
+		{`"dngb2"`, NewHashInt(EncodeStrToInt("dngb2"), 25)},
+		{`1234`, NewHashInt(1234, 20)},
+		{`"dngb"`, NewHashInt(EncodeStrToInt("dngb"), 20)},
+		{`1234`, NewHashInt(1234, 20)},
+	} {
+		if err := json.Unmarshal([]byte(c.data), &col); err != nil || col.Hash != c.want {
+			t.Errorf("HashJSON.Unmarshal(%s) = %v, %v, want %v", c.data, col.Hash, err, c.want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`1048576`), &col); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("HashJSON.Unmarshal(1048576) = %v, want %v", err, ErrInvalidHash)
+	}
+	if b, err := json.Marshal(HashJSON{Hash: NewHashInt(1234, 20)}); err != nil || string(b) != "1234" {
+		t.Errorf("HashJSON.Marshal = %s, %v, want 1234", b, err)
+	}
+}
+
+func BenchmarkParseHash(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		ParseHash(testHash)
+	}
+}
@@ -0,0 +1,215 @@
+package geohash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// ErrInvalidHashList is returned by OpenHashList for data that is truncated or was not produced by PackHashes.
+var ErrInvalidHashList = errors.New("geohash: invalid hash list")
+
+const (
+	// hashListBlock is the number of geohash integers in each block of a packed hash list.
+	hashListBlock = 128
+
+	// hashListEntry is the size in bytes of a skip index entry: the first integer of a block and the offset of its data.
+	hashListEntry = 12
+)
+
+// PackHashes returns a compact encoding of a list of geohash integers, such as those returned by EncodeInt.
+// The integers are sorted and duplicates removed, then split into blocks of 128.
+// Each block stores the differences between consecutive integers bit packed at the width of its largest difference,
+// so nearby cells of a covering take a few bits each rather than 8 bytes.
+// A skip index holds the first integer of each block, so an opened HashList answers Contains and Range
+// by binary searching the index and unpacking a single block rather than the whole list.
+//
+// Block offsets are 32 bits, limiting the encoded data to 4 GiB, roughly 500 million sparse integers.
+//
+// The format is: uvarint number of integers, then for each block a 12-byte index entry of the little endian
+// first integer and little endian uint32 offset of its data, then the data of each block:
+// one byte bit width and the remaining differences of the block packed least significant bit first.
+func PackHashes(hashes []uint64) []byte {
+	hashes = slices.Compact(slices.Sorted(slices.Values(hashes)))
+	blocks := (len(hashes) + hashListBlock - 1) / hashListBlock
+
+	data := binary.AppendUvarint(nil, uint64(len(hashes)))
+	index := len(data)
+	data = append(data, make([]byte, blocks*hashListEntry)...)
+
+	start := len(data)
+	for b := 0; b < blocks; b++ {
+		block := hashes[b*hashListBlock : min(len(hashes), (b+1)*hashListBlock)]
+
+		entry := data[index+b*hashListEntry:]
+		binary.LittleEndian.PutUint64(entry, block[0])
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(data)-start))
+
+		width := 0
+		for i := 1; i < len(block); i++ {
+			width = max(width, bits.Len64(block[i]-block[i-1]))
+		}
+		data = append(data, byte(width))
+
+		packed := make([]byte, hashListBytes(len(block), width))
+		for i := 1; i < len(block); i++ {
+			packBits(packed, (i-1)*width, width, block[i]-block[i-1])
+		}
+		data = append(data, packed...)
+	}
+
+	return data
+}
+
+// HashList is a read only view of a list of geohash integers encoded by PackHashes.
+// Lookups unpack only the block that may hold an integer, so a HashList uses no memory beyond the encoded data.
+// A HashList is safe for concurrent use.
+type HashList struct {
+	count  int
+	index  []byte
+	blocks []byte
+}
+
+// OpenHashList returns a HashList reading the encoded data, which must not be modified while the HashList is in use.
+// The skip index and block sizes are validated up front, so later lookups cannot fail.
+// ErrInvalidHashList is returned for truncated or malformed data.
+func OpenHashList(data []byte) (*HashList, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: invalid number of hashes", ErrInvalidHashList)
+	}
+	data = data[n:]
+
+	blocks := (int(count) + hashListBlock - 1) / hashListBlock
+	if len(data) < blocks*hashListEntry {
+		return nil, fmt.Errorf("%w: truncated skip index", ErrInvalidHashList)
+	}
+	l := &HashList{count: int(count), index: data[:blocks*hashListEntry], blocks: data[blocks*hashListEntry:]}
+
+	// Each block must start where the previous one ends, hold integers greater than the previous block,
+	// and have a width that can represent a difference.
+	end := 0
+	for b := 0; b < blocks; b++ {
+		first, offset := l.entry(b)
+		if offset != end || offset >= len(l.blocks) {
+			return nil, fmt.Errorf("%w: invalid offset of block %d", ErrInvalidHashList, b)
+		}
+		if b > 0 {
+			if prev, _ := l.entry(b - 1); prev >= first {
+				return nil, fmt.Errorf("%w: block %d is not sorted", ErrInvalidHashList, b)
+			}
+		}
+
+		width := int(l.blocks[offset])
+		if width > 64 || width == 0 && l.blockLen(b) > 1 {
+			return nil, fmt.Errorf("%w: invalid bit width of block %d", ErrInvalidHashList, b)
+		}
+		end = offset + 1 + hashListBytes(l.blockLen(b), width)
+	}
+	if end != len(l.blocks) {
+		return nil, fmt.Errorf("%w: %d bytes of block data, want %d", ErrInvalidHashList, len(l.blocks), end)
+	}
+
+	return l, nil
+}
+
+// Len returns the number of geohash integers in the list.
+func (l *HashList) Len() int {
+	return l.count
+}
+
+// Contains returns true if the list holds the geohash integer.
+func (l *HashList) Contains(hash uint64) bool {
+	for h := range l.Range(IntRange{Min: hash, Max: hash}) {
+		return h == hash
+	}
+	return false
+}
+
+// All returns an iterator over the geohash integers of the list in ascending order.
+func (l *HashList) All() iter.Seq[uint64] {
+	return l.Range(IntRange{Min: 0, Max: 1<<64 - 1})
+}
+
+// Range returns an iterator over the geohash integers of the list within the inclusive range r, in ascending order.
+// Iteration starts from the block found in the skip index and stops at the first integer beyond r.
+// Ranges from BoxRanges or Set.Ranges select the integers within a region.
+func (l *HashList) Range(r IntRange) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		if r.Min > r.Max {
+			return
+		}
+
+		// The last block starting at or before r.Min, as earlier blocks only hold smaller integers.
+		blocks := len(l.index) / hashListEntry
+		start := sort.Search(blocks, func(b int) bool {
+			first, _ := l.entry(b)
+			return first > r.Min
+		})
+		start = max(0, start-1)
+
+		for b := start; b < blocks; b++ {
+			h, offset := l.entry(b)
+			width := int(l.blocks[offset])
+			packed := l.blocks[offset+1:]
+
+			for i := 0; i < l.blockLen(b); i++ {
+				if i > 0 {
+					h += unpackBits(packed, (i-1)*width, width)
+				}
+				if h > r.Max {
+					return
+				}
+				if h >= r.Min && !yield(h) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// entry returns the first integer and data offset of block b from the skip index.
+func (l *HashList) entry(b int) (uint64, int) {
+	e := l.index[b*hashListEntry:]
+	return binary.LittleEndian.Uint64(e), int(binary.LittleEndian.Uint32(e[8:]))
+}
+
+// blockLen returns the number of integers in block b, which is 128 except for the last block.
+func (l *HashList) blockLen(b int) int {
+	return min(hashListBlock, l.count-b*hashListBlock)
+}
+
+// hashListBytes returns the bytes needed to pack the differences of a block of n integers at a bit width.
+func hashListBytes(n, width int) int {
+	return ((n-1)*width + 7) / 8
+}
+
+// packBits writes the low width bits of v to buf starting at bit pos, least significant bit first.
+// The bits of buf being written must be zero.
+func packBits(buf []byte, pos, width int, v uint64) {
+	for width > 0 {
+		shift := pos % 8
+		n := min(8-shift, width)
+		buf[pos/8] |= byte(v&(1<<n-1)) << shift
+		v >>= n
+		pos += n
+		width -= n
+	}
+}
+
+// unpackBits reads width bits from buf starting at bit pos, least significant bit first.
+func unpackBits(buf []byte, pos, width int) uint64 {
+	var v uint64
+	for read := 0; read < width; {
+		shift := pos % 8
+		n := min(8-shift, width-read)
+		v |= uint64(buf[pos/8]>>shift&(1<<n-1)) << read
+		pos += n
+		read += n
+	}
+	return v
+}
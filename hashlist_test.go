@@ -0,0 +1,147 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// testHashes returns n geohash integers of points clustered around a few cities, with duplicates.
+func testHashes(n int, seed int64) []uint64 {
+	r := rand.New(rand.NewSource(seed))
+	cities := []Point{{40.7128, -74.0060}, {51.5074, -0.1278}, {-33.8688, 151.2093}, {35.6762, 139.6503}}
+
+	hashes := make([]uint64, n)
+	for i := range hashes {
+		c := cities[r.Intn(len(cities))]
+		hashes[i] = EncodeInt(c.Lat+r.NormFloat64()*0.1, c.Lng+r.NormFloat64()*0.1)
+	}
+	return hashes
+}
+
+func TestHashList(t *testing.T) {
+	hashes := append(testHashes(5000, 1), 0, math.MaxUint64, math.MaxUint64-1, 1<<63)
+	want := slices.Compact(slices.Sorted(slices.Values(hashes)))
+
+	l, err := OpenHashList(PackHashes(hashes))
+	if err != nil {
+		t.Fatalf("OpenHashList = %s", err.Error())
+	}
+	if l.Len() != len(want) {
+		t.Errorf("Len = %d, want %d", l.Len(), len(want))
+	}
+	if res := slices.Collect(l.All()); !slices.Equal(res, want) {
+		t.Errorf("All = %d hashes, want %d", len(res), len(want))
+	}
+
+	for _, h := range want {
+		if !l.Contains(h) {
+			t.Errorf("Contains(%d) = false, want true", h)
+		}
+		if h > 0 && !slices.Contains(want, h-1) && l.Contains(h-1) {
+			t.Errorf("Contains(%d) = true, want false", h-1)
+		}
+	}
+
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		lo, hi := want[r.Intn(len(want))]-uint64(r.Intn(1000)), want[r.Intn(len(want))]
+		if i%2 == 0 {
+			// Ranges of a cell, as produced by BoxRanges.
+			hi = lo | (1<<(64-5*r.Intn(12)) - 1)
+		}
+		rng := IntRange{Min: lo, Max: hi}
+
+		var expected []uint64
+		for _, h := range want {
+			if h >= lo && h <= hi {
+				expected = append(expected, h)
+			}
+		}
+		if res := slices.Collect(l.Range(rng)); !slices.Equal(res, expected) {
+			t.Errorf("Range(%v) = %d hashes, want %d", rng, len(res), len(expected))
+		}
+	}
+
+	// Clustered points take fewer than 8 bytes each, and the contiguous cells of a covering take a few bits each.
+	if data := PackHashes(testHashes(5000, 1)); len(data) > 5000*6 {
+		t.Errorf("PackHashes = %d bytes for 5000 hashes, want at most 30000", len(data))
+	}
+	var cells []uint64
+	boxCells(Box{MinLat: 40.5, MaxLat: 41, MinLng: -74.5, MaxLng: -73.5}, 30, func(h uint64) bool {
+		cells = append(cells, h)
+		return true
+	})
+	if data := PackHashes(cells); len(data) > len(cells) {
+		t.Errorf("PackHashes = %d bytes for %d cells, want at most %d", len(data), len(cells), len(cells))
+	}
+}
+
+func TestHashListSmall(t *testing.T) {
+	tests := [][]uint64{
+		nil,
+		{42},
+		{42, 42, 42},
+		{0, math.MaxUint64},
+	}
+
+	for _, test := range tests {
+		l, err := OpenHashList(PackHashes(test))
+		if err != nil {
+			t.Fatalf("OpenHashList(%v) = %s", test, err.Error())
+		}
+		want := slices.Compact(slices.Sorted(slices.Values(test)))
+		if res := slices.Collect(l.All()); !slices.Equal(res, want) {
+			t.Errorf("All = %v, want %v", res, want)
+		}
+		if l.Contains(7) {
+			t.Errorf("Contains(7) = true, want false")
+		}
+	}
+}
+
+func TestOpenHashListInvalid(t *testing.T) {
+	data := PackHashes(testHashes(300, 3))
+
+	unsorted := slices.Clone(data)
+	copy(unsorted[2+hashListEntry:], unsorted[2:10])
+
+	badWidth := slices.Clone(data)
+	badWidth[2+3*hashListEntry] = 65
+
+	tests := [][]byte{
+		nil,
+		{0xff},
+		{5},
+		data[:20],
+		data[:len(data)-1],
+		append(slices.Clone(data), 0),
+		unsorted,
+		badWidth,
+	}
+
+	for i, test := range tests {
+		if _, err := OpenHashList(test); !errors.Is(err, ErrInvalidHashList) {
+			t.Errorf("OpenHashList(%d) = %v, want %v", i, err, ErrInvalidHashList)
+		}
+	}
+}
+
+func BenchmarkPackHashes(b *testing.B) {
+	hashes := testHashes(100000, 1)
+	var data []byte
+	for n := 0; n < b.N; n++ {
+		data = PackHashes(hashes)
+	}
+	b.ReportMetric(float64(len(data))/float64(len(hashes)), "bytes/hash")
+}
+
+func BenchmarkHashListContains(b *testing.B) {
+	hashes := testHashes(100000, 1)
+	l, _ := OpenHashList(PackHashes(hashes))
+	for n := 0; n < b.N; n++ {
+		l.Contains(hashes[n%len(hashes)])
+	}
+}
@@ -0,0 +1,199 @@
+package geohash
+
+import "math"
+
+// Curve is a space-filling curve that maps lat, lng coordinates to integer keys.
+// ZOrder and Hilbert implement Curve, allowing storage layouts to switch curves without rewriting callers.
+// Both curves share the uint32 quantization of encodeRange, so keys of the same bit precision identify cells of the same size.
+type Curve interface {
+	// EncodeIntPrecision returns the key of lat, lng coordinates based on the provided bit precision of 1 to 64.
+	EncodeIntPrecision(lat, lng float64, bits int) uint64
+
+	// DecodeIntPrecision returns the southwest corner of the cell described by a key of specified precision.
+	DecodeIntPrecision(hash uint64, bits int) (float64, float64)
+
+	// DecodeIntBox returns the bounding box of the cell described by a key of specified precision.
+	DecodeIntBox(hash uint64, bits int) Box
+
+	// BoxRanges returns the merged ranges of keys stored at columnBits precision within cells of bits precision overlapping box.
+	BoxRanges(box Box, bits, columnBits int) []IntRange
+}
+
+var (
+	// ZOrder is the Curve of standard geohash integers, using the package level functions.
+	ZOrder Curve = zOrder{}
+
+	// Hilbert is the Curve of Hilbert keys, using the Hilbert functions.
+	Hilbert Curve = hilbert{}
+)
+
+type zOrder struct{}
+
+func (zOrder) EncodeIntPrecision(lat, lng float64, bits int) uint64 {
+	return EncodeIntPrecision(lat, lng, bits)
+}
+
+func (zOrder) DecodeIntPrecision(hash uint64, bits int) (float64, float64) {
+	return DecodeIntPrecision(hash, bits)
+}
+
+func (zOrder) DecodeIntBox(hash uint64, bits int) Box {
+	return WGS84.DecodeIntBox(hash, bits)
+}
+
+func (zOrder) BoxRanges(box Box, bits, columnBits int) []IntRange {
+	return BoxRanges(box, bits, columnBits)
+}
+
+type hilbert struct{}
+
+func (hilbert) EncodeIntPrecision(lat, lng float64, bits int) uint64 {
+	return EncodeHilbertPrecision(lat, lng, bits)
+}
+
+func (hilbert) DecodeIntPrecision(hash uint64, bits int) (float64, float64) {
+	return DecodeHilbertPrecision(hash, bits)
+}
+
+func (hilbert) DecodeIntBox(hash uint64, bits int) Box {
+	return DecodeHilbertBox(hash, bits)
+}
+
+func (hilbert) BoxRanges(box Box, bits, columnBits int) []IntRange {
+	return HilbertRanges(box, bits, columnBits)
+}
+
+// EncodeHilbert returns the uint64 Hilbert key of lat, lng coordinates based on the max bit precision of 64.
+// Like a geohash integer, each pair of bits selects a quadrant of the cell described by the preceding bits.
+// Unlike Z-order, the Hilbert curve rotates and reflects the quadrant order so consecutive keys are always adjacent cells.
+// This avoids the jumps at quadrant boundaries where nearby points receive distant geohash integers.
+func EncodeHilbert(lat, lng float64) uint64 {
+	return EncodeHilbertPrecision(lat, lng, bitsMax)
+}
+
+// EncodeHilbertPrecision returns the uint64 Hilbert key of lat, lng coordinates based on the provided bit precision.
+// Acceptable bit values are 1 to 64. An odd bit precision describes half of a cell, split along the axis the curve crosses.
+// Coordinates on or beyond the max edges of 90 and 180 are placed in the northernmost row and easternmost column.
+func EncodeHilbertPrecision(lat, lng float64, bits int) uint64 {
+	bits = validate(bitsMin, bitsMax, bits)
+	lat32 := clampRange(lat, -latMax, latMax)
+	lng32 := clampRange(lng, -lngMax, lngMax)
+	return hilbertIndex(lat32, lng32) >> (64 - bits)
+}
+
+// DecodeHilbert returns the estimated lat, lng coordinates for a Hilbert key.
+// Assumes max precision of 64 bits.
+func DecodeHilbert(hash uint64) (float64, float64) {
+	return DecodeHilbertPrecision(hash, bitsMax)
+}
+
+// DecodeHilbertPrecision returns the estimated lat, lng coordinates for a Hilbert key of specified precision.
+// As with DecodeIntPrecision, this is the southwest corner of the cell.
+func DecodeHilbertPrecision(hash uint64, bits int) (float64, float64) {
+	box := DecodeHilbertBox(hash, bits)
+	return box.MinLat, box.MinLng
+}
+
+// DecodeHilbertBox returns the bounding box of the cell described by a Hilbert key of specified precision.
+// For an odd bit precision, the box is the union of the two cells of the following bit.
+func DecodeHilbertBox(hash uint64, bits int) Box {
+	bits = validate(bitsMin, bitsMax, bits)
+	if bits%2 == 1 {
+		lo := DecodeHilbertBox(hash<<1, bits+1)
+		hi := DecodeHilbertBox(hash<<1|1, bits+1)
+		return Box{
+			MinLat: math.Min(lo.MinLat, hi.MinLat),
+			MaxLat: math.Max(lo.MaxLat, hi.MaxLat),
+			MinLng: math.Min(lo.MinLng, hi.MinLng),
+			MaxLng: math.Max(lo.MaxLng, hi.MaxLng),
+		}
+	}
+
+	// Padding the key with zeros selects a point within the cell. Its high bits are the row and column of the cell.
+	n := bits / 2
+	lat32, lng32 := hilbertPoint(hash << (64 - bits))
+
+	b := Box{}
+	b.MinLat, b.MaxLat = WGS84.edges(lat32>>(32-n), n, -latMax, latMax)
+	b.MinLng, b.MaxLng = WGS84.edges(lng32>>(32-n), n, -lngMax, lngMax)
+	return b
+}
+
+// HilbertRanges returns the ranges of Hilbert keys stored at columnBits precision that fall within cells of bits precision overlapping box.
+// Cells are visited using the integer grid, converted to the column precision, sorted, and contiguous ranges are merged.
+// As consecutive Hilbert keys are adjacent cells, a box usually produces fewer ranges than BoxRanges does for geohash integers.
+// Acceptable bit values are 2 to 64 and bits is limited to columnBits. Odd bit values are rounded down, as cells divide both axes.
+// As with BoxRanges, if more than 65536 cells of bits precision overlap box, bits is lowered until no more than 65536 do.
+// The box is assumed not to cross the antimeridian (MinLng <= MaxLng).
+func HilbertRanges(box Box, bits, columnBits int) []IntRange {
+	columnBits = validate(bitsMin, bitsMax, columnBits)
+	n := boxBits(box, validate(bitsMin, columnBits, bits)&^1, boxRangesMaxCells) / 2
+	if n == 0 {
+		return []IntRange{{Min: 0, Max: 1<<columnBits - 1}}
+	}
+	shift := columnBits - 2*n
+
+	lat0, lat1 := gridSpan(box.MinLat, box.MaxLat, latMax, n)
+	lng0, lng1 := gridSpan(box.MinLng, box.MaxLng, lngMax, n)
+
+	// uint64 counters avoid overflowing the loop condition when the last row or column is math.MaxUint32.
+	ranges := []IntRange{}
+	for lat := uint64(lat0); lat <= uint64(lat1); lat++ {
+		for lng := uint64(lng0); lng <= uint64(lng1); lng++ {
+			hash := hilbertIndex(uint32(lat)<<(32-n), uint32(lng)<<(32-n)) >> (64 - 2*n)
+			ranges = append(ranges, IntRange{Min: hash << shift, Max: hash<<shift | (1<<shift - 1)})
+		}
+	}
+
+	return mergeRanges(ranges)
+}
+
+// hilbertIndex returns the 64-bit Hilbert key of the uint32 lat (y) and lng (x) values.
+// On each iteration, the quadrant of the current bit of x and y is appended to the key in curve order (SW, NW, NE, SE).
+// The remaining bits are then rotated and reflected into the orientation of the curve within that quadrant.
+// Reference: https://en.wikipedia.org/wiki/Hilbert_curve
+func hilbertIndex(lat32, lng32 uint32) uint64 {
+	x, y := lng32, lat32
+
+	var hash uint64
+	for s := uint32(1) << 31; s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		hash += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		x, y = hilbertRotate(math.MaxUint32, x, y, rx, ry)
+	}
+	return hash
+}
+
+// hilbertPoint returns the uint32 lat (y) and lng (x) values of a 64-bit Hilbert key, the inverse of hilbertIndex.
+// The key is consumed two bits at a time from the lowest quadrant, rotating the point built so far into each enclosing quadrant.
+func hilbertPoint(hash uint64) (uint32, uint32) {
+	var x, y uint32
+	for s := uint64(1); s < 1<<32; s <<= 1 {
+		rx := uint32(1 & (hash >> 1))
+		ry := uint32(1 & (hash ^ uint64(rx)))
+		x, y = hilbertRotate(uint32(s-1), x, y, rx, ry)
+		x += uint32(s) * rx
+		y += uint32(s) * ry
+		hash >>= 2
+	}
+	return y, x
+}
+
+// hilbertRotate rotates and reflects x, y within a quadrant whose coordinates are limited by mask.
+// The lower left quadrant is transposed, and the lower right quadrant is reflected and transposed.
+func hilbertRotate(mask, x, y, rx, ry uint32) (uint32, uint32) {
+	if ry == 0 {
+		if rx == 1 {
+			x = mask - x
+			y = mask - y
+		}
+		x, y = y, x
+	}
+	return x, y
+}
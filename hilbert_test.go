@@ -0,0 +1,175 @@
+package geohash
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEncodeHilbertPrecision(t *testing.T) {
+	// The order 2 curve visits the 4x4 grid of cells starting southwest and ending southeast.
+	// Reference: https://en.wikipedia.org/wiki/Hilbert_curve
+	order2 := [16][2]int{
+		{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 2}, {0, 3}, {1, 3}, {1, 2},
+		{2, 2}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {2, 1}, {2, 0}, {3, 0},
+	}
+
+	for i, xy := range order2 {
+		lng := -lngMax + (float64(xy[0])+0.5)*lngMax/2
+		lat := -latMax + (float64(xy[1])+0.5)*latMax/2
+		if res := EncodeHilbertPrecision(lat, lng, 4); res != uint64(i) {
+			t.Errorf("EncodeHilbertPrecision(%f, %f, 4) = %d, want %d", lat, lng, res, i)
+		}
+		if res := EncodeHilbertPrecision(lat, lng, 2); res != uint64(i/4) {
+			t.Errorf("EncodeHilbertPrecision(%f, %f, 2) = %d, want %d", lat, lng, res, i/4)
+		}
+	}
+
+	// The max edges are placed in the northeast corner cell rather than wrapping to the southwest.
+	for _, c := range []struct {
+		lat, lng float64
+		bits     int
+		want     uint64
+	}{
+		{latMax, lngMax, 2, 2},
+		{latMax, lngMax, 4, 10},
+		{-latMax, -lngMax, 2, 0},
+		{-latMax, -lngMax, 4, 0},
+		{-latMax, -lngMax, 64, 0},
+	} {
+		if res := EncodeHilbertPrecision(c.lat, c.lng, c.bits); res != c.want {
+			t.Errorf("EncodeHilbertPrecision(%f, %f, %d) = %d, want %d", c.lat, c.lng, c.bits, res, c.want)
+		}
+	}
+	if res := DecodeHilbertBox(EncodeHilbert(latMax, lngMax), 64); res.MaxLat != latMax || res.MaxLng != lngMax {
+		t.Errorf("DecodeHilbertBox(EncodeHilbert(%f, %f)) = %v, want the northeast corner cell", latMax, lngMax, res)
+	}
+}
+
+func TestDecodeHilbert(t *testing.T) {
+	for _, c := range testCases {
+		hash := EncodeHilbert(c.lat, c.lng)
+		if res := DecodeHilbertBox(hash>>4, 60); !res.Contains(c.lat, c.lng) {
+			t.Errorf("DecodeHilbertBox = %+v, does not contain %f, %f", res, c.lat, c.lng)
+		}
+
+		lat, lng := DecodeHilbert(hash)
+		if res := EncodeHilbert(lat, lng); res != hash {
+			t.Errorf("EncodeHilbert(DecodeHilbert(%x)) = %x", hash, res)
+		}
+
+		// Hilbert cells share the size of geohash cells of the same even bit precision.
+		hBox, zBox := DecodeHilbertBox(hash>>34, 30), DecodeBox(EncodePrecision(c.lat, c.lng, 6))
+		if hBox != zBox {
+			t.Errorf("DecodeHilbertBox = %+v, want %+v", hBox, zBox)
+		}
+	}
+
+	// An odd bit precision is half of a cell.
+	box := DecodeHilbertBox(1, 3)
+	if box.Height()*box.Width() != DecodeHilbertBox(0, 2).Height()*DecodeHilbertBox(0, 2).Width()/2 {
+		t.Errorf("DecodeHilbertBox = %+v, want half of a 2 bit cell", box)
+	}
+}
+
+func TestHilbertAdjacency(t *testing.T) {
+	// Consecutive keys are always cells sharing an edge.
+	const bits = 16
+	prev := DecodeHilbertBox(0, bits)
+	for i := uint64(1); i < 1<<bits; i++ {
+		box := DecodeHilbertBox(i, bits)
+		sharesLat := box.MinLat == prev.MinLat && (box.MinLng == prev.MaxLng || box.MaxLng == prev.MinLng)
+		sharesLng := box.MinLng == prev.MinLng && (box.MinLat == prev.MaxLat || box.MaxLat == prev.MinLat)
+		if !sharesLat && !sharesLng {
+			t.Fatalf("DecodeHilbertBox(%d) = %+v, not adjacent to %+v", i, box, prev)
+		}
+		prev = box
+	}
+}
+
+func TestHilbertRanges(t *testing.T) {
+	// A box matching a single cell produces a single range of the cell's children.
+	hash := EncodeHilbertPrecision(40.7, -74, 20)
+	box := DecodeHilbertBox(hash, 20)
+	want := []IntRange{{hash << 44, hash<<44 | (1<<44 - 1)}}
+	for _, bits := range []int{20, 21, 30} {
+		if res := HilbertRanges(box, bits, 64); !slices.Equal(res, want) {
+			t.Errorf("HilbertRanges(%d) = %x, want %x", bits, res, want)
+		}
+	}
+
+	if res := HilbertRanges(box, 1, 64); !slices.Equal(res, []IntRange{{0, 1<<64 - 1}}) {
+		t.Errorf("HilbertRanges = %x, want the whole curve", res)
+	}
+
+	for _, c := range testCases {
+		box := DecodeBox(c.hash[:5])
+		box.MaxLat += box.Height()
+		box.MaxLng += box.Width()
+
+		hash := EncodeHilbert(c.lat, c.lng)
+		found := false
+		for _, r := range HilbertRanges(box, 26, 64) {
+			found = found || (hash >= r.Min && hash <= r.Max)
+		}
+		if !found {
+			t.Errorf("HilbertRanges = does not contain %x", hash)
+		}
+	}
+}
+
+func TestHilbertRangesCoarsen(t *testing.T) {
+	world := Box{MinLat: -latMax, MaxLat: latMax, MinLng: -lngMax, MaxLng: lngMax}
+	if res := HilbertRanges(world, 64, 64); !slices.Equal(res, []IntRange{{0, 1<<64 - 1}}) {
+		t.Errorf("HilbertRanges = %x, want the whole curve", res)
+	}
+}
+
+func TestCurve(t *testing.T) {
+	for _, curve := range []Curve{ZOrder, Hilbert} {
+		for _, c := range testCases {
+			hash := curve.EncodeIntPrecision(c.lat, c.lng, 40)
+			box := curve.DecodeIntBox(hash, 40)
+			if !box.Contains(c.lat, c.lng) {
+				t.Errorf("DecodeIntBox = %+v, does not contain %f, %f", box, c.lat, c.lng)
+			}
+			if lat, lng := curve.DecodeIntPrecision(hash, 40); lat != box.MinLat || lng != box.MinLng {
+				t.Errorf("DecodeIntPrecision = %f, %f, want %f, %f", lat, lng, box.MinLat, box.MinLng)
+			}
+			if len(curve.BoxRanges(box, 40, 64)) != 1 {
+				t.Errorf("BoxRanges = %v, want a single range", curve.BoxRanges(box, 40, 64))
+			}
+		}
+	}
+}
+
+func BenchmarkEncodeHilbert(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		EncodeHilbert(testLat, testLng)
+	}
+}
+
+func BenchmarkDecodeHilbert(b *testing.B) {
+	hash := EncodeHilbert(testLat, testLng)
+	for n := 0; n < b.N; n++ {
+		DecodeHilbert(hash)
+	}
+}
+
+// The box ranges benchmarks cover the same box with each curve, reporting the number of ranges produced.
+var benchRangesBox = Box{MinLat: 40.70, MaxLat: 40.80, MinLng: -74.02, MaxLng: -73.93}
+
+func BenchmarkBoxRangesZOrder(b *testing.B) {
+	benchmarkRanges(b, ZOrder)
+}
+
+func BenchmarkBoxRangesHilbert(b *testing.B) {
+	benchmarkRanges(b, Hilbert)
+}
+
+func benchmarkRanges(b *testing.B, curve Curve) {
+	var ranges []IntRange
+	for n := 0; n < b.N; n++ {
+		ranges = curve.BoxRanges(benchRangesBox, 30, 64)
+	}
+	b.ReportMetric(float64(len(ranges)), "ranges")
+}
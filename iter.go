@@ -0,0 +1,142 @@
+package geohash
+
+import "iter"
+
+// Children returns an iterator over the 32 cells one character longer than hash, in Z-order.
+// The iterator is empty for invalid geohash strings and hashes of 20 or more characters.
+func Children(hash string) iter.Seq[string] {
+	return Descendants(hash, 1)
+}
+
+// Descendants returns an iterator over the cells exactly depth characters longer than hash, in Z-order.
+// A depth of 0 yields hash itself. There are 32^depth descendants, so the cells are generated as they are consumed.
+// The iterator is empty for invalid geohash strings, a negative depth, or descendants exceeding 20 characters.
+func Descendants(hash string, depth int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if depth < 0 || len(hash)+depth > precisionHigh || Base32Alphabet.Validate(hash) != nil {
+			return
+		}
+
+		buf := make([]byte, len(hash)+depth)
+		copy(buf, hash)
+		descend(buf, len(hash), yield)
+	}
+}
+
+// descend fills buf from index i with every combination of base32 characters, yielding each completed string.
+// Returns false when yield returns false.
+func descend(buf []byte, i int, yield func(string) bool) bool {
+	if i == len(buf) {
+		return yield(string(buf))
+	}
+	for j := 0; j < len(base32); j++ {
+		buf[i] = base32[j]
+		if !descend(buf, i+1, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// CellsInBox returns an iterator over the geohash strings of every cell of the provided character precision that overlaps box.
+// Acceptable precision values are 1 to 12 characters.
+// Cells are ordered row by row starting in the southwest corner, as returned by CoverBox.
+// A box crossing the antimeridian (MinLng > MaxLng) is covered as two boxes split at the antimeridian, west first.
+func CellsInBox(box Box, precision int) iter.Seq[string] {
+	precision = validate(precisionMin, precisionMax, precision)
+
+	return func(yield func(string) bool) {
+		for _, b := range splitBox(box) {
+			done := false
+			boxCells(b, precision*5, func(hash uint64) bool {
+				done = !yield(EncodeIntToStr(hash, precision))
+				return !done
+			})
+			if done {
+				return
+			}
+		}
+	}
+}
+
+// Ring returns an iterator over the cells exactly k rows or columns from hash, where k of 0 yields hash itself.
+// Cells are ordered clockwise starting with the cell k rows north, so a ring of 1 matches the order of Neighbors.
+// Longitude wraps across the antimeridian and cells beyond the poles are omitted.
+// Once k exceeds half the columns of the precision, the ring holds only the rows k north and south,
+// and once no cell is k rows or columns away, it is empty. A huge k returns immediately rather than walking its perimeter.
+// The iterator is empty for invalid geohash strings, a negative k, and the empty string when k is not 0.
+func Ring(hash string, k int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		h, err := ParseHash(hash)
+		if err != nil || k < 0 {
+			return
+		}
+		if h.bits == 0 {
+			if k == 0 {
+				yield(hash)
+			}
+			return
+		}
+
+		for n := range RingInt(h.value, h.bits, k) {
+			if !yield(EncodeIntToStr(n, len(hash))) {
+				return
+			}
+		}
+	}
+}
+
+// RingInt returns an iterator over the geohash integers of the cells exactly k rows or columns from a geohash integer.
+// This is the integer form of Ring using the layout of EncodeIntPrecision. Acceptable bit values are 1 to 64.
+// The iterator is empty for a negative k.
+func RingInt(hash uint64, bits, k int) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		if k < 0 {
+			return
+		}
+		b := validate(bitsMin, bitsMax, bits)
+		gridRing(hash, b, k, ringSeen(b, min(k, gridRingMax(b))), yield)
+	}
+}
+
+// Disk returns an iterator over the cells within k rows or columns of hash, including hash itself.
+// Cells are ordered ring by ring from hash outward, each ring ordered as Ring, and each cell is yielded once.
+// A k beyond the size of the grid yields every cell of the precision, stopping at the last ring that contains cells.
+// The iterator is empty for invalid geohash strings and a negative k. The empty string yields itself.
+func Disk(hash string, k int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		h, err := ParseHash(hash)
+		if err != nil || k < 0 {
+			return
+		}
+		if h.bits == 0 {
+			yield(hash)
+			return
+		}
+
+		for n := range DiskInt(h.value, h.bits, k) {
+			if !yield(EncodeIntToStr(n, len(hash))) {
+				return
+			}
+		}
+	}
+}
+
+// DiskInt returns an iterator over the geohash integers of the cells within k rows or columns of a geohash integer.
+// This is the integer form of Disk using the layout of EncodeIntPrecision. Acceptable bit values are 1 to 64.
+// The iterator is empty for a negative k.
+func DiskInt(hash uint64, bits, k int) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		if k < 0 {
+			return
+		}
+		b := validate(bitsMin, bitsMax, bits)
+		k := min(k, gridRingMax(b))
+		seen := ringSeen(b, k)
+		for r := 0; r <= k; r++ {
+			if !gridRing(hash, b, r, seen, yield) {
+				return
+			}
+		}
+	}
+}
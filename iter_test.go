@@ -0,0 +1,201 @@
+package geohash
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestChildren(t *testing.T) {
+	res := slices.Collect(Children("dqcj"))
+	if len(res) != 32 || res[0] != "dqcj0" || res[31] != "dqcjz" {
+		t.Errorf("Children = %v, want dqcj0 to dqcjz", res)
+	}
+
+	// Children are in Z-order, matching the order of their integers.
+	if !slices.IsSortedFunc(res, func(a, b string) int { return cmp.Compare(EncodeStrToInt(a), EncodeStrToInt(b)) }) {
+		t.Errorf("Children = %v, want Z-order", res)
+	}
+
+	for _, hash := range []string{"dqcja", "dqcjqcpeemc0k4ftxyzw"} {
+		if res := slices.Collect(Children(hash)); len(res) != 0 {
+			t.Errorf("Children(%s) = %v, want empty", hash, res)
+		}
+	}
+}
+
+func TestChildrenSet(t *testing.T) {
+	// Children yields the same cells as the children helper used by the Set tests, which merge back into their parent.
+	res := slices.Collect(Children("dqcj"))
+	if want := children("dqcj"); !slices.Equal(res, want) {
+		t.Errorf("Children = %v, want %v", res, want)
+	}
+	if s, _ := NewSet(res...); !slices.Equal(setHashes(s), []string{"dqcj"}) {
+		t.Errorf("NewSet(Children) = %v, want [dqcj]", setHashes(s))
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	res := slices.Collect(Descendants("dqc", 2))
+	if len(res) != 1024 || res[0] != "dqc00" || res[1] != "dqc01" || res[1023] != "dqczz" {
+		t.Errorf("Descendants = %d cells, want 1024 from dqc00 to dqczz", len(res))
+	}
+
+	if res := slices.Collect(Descendants("dqc", 0)); !slices.Equal(res, []string{"dqc"}) {
+		t.Errorf("Descendants = %v, want [dqc]", res)
+	}
+
+	// Stopping early does not generate the remaining descendants.
+	n := 0
+	for range Descendants("", 12) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("Descendants yielded %d cells after break, want 3", n)
+	}
+}
+
+func TestCellsInBox(t *testing.T) {
+	box := DecodeBox("dqcjq")
+	box.MaxLat += box.Height() / 2
+	box.MaxLng += box.Width() / 2
+
+	want := []string{"dqcjq", "dqcjr", "dqcjw", "dqcjx"}
+	if res := slices.Collect(CellsInBox(box, 5)); !slices.Equal(res, want) {
+		t.Errorf("CellsInBox = %v, want %v", res, want)
+	}
+
+	// Stopping early across the antimeridian split does not continue into the second box.
+	box = Box{MinLat: 1, MaxLat: 2, MinLng: 179, MaxLng: -179}
+	for hash := range CellsInBox(box, 1) {
+		if hash != "x" {
+			t.Errorf("CellsInBox = %s, want x", hash)
+		}
+		break
+	}
+}
+
+func TestRing(t *testing.T) {
+	for _, c := range neighborsCases {
+		if res := slices.Collect(Ring(c.hash, 1)); !slices.Equal(res, c.neighbors) {
+			t.Errorf("Ring(%s, 1) = %v, want %v", c.hash, res, c.neighbors)
+		}
+	}
+
+	if res := slices.Collect(Ring("dqcjq", 0)); !slices.Equal(res, []string{"dqcjq"}) {
+		t.Errorf("Ring(dqcjq, 0) = %v, want [dqcjq]", res)
+	}
+
+	// Every cell of the second ring is a neighbor of the first ring and not in the first ring.
+	first := slices.Collect(Ring("dqcjq", 1))
+	second := slices.Collect(Ring("dqcjq", 2))
+	if len(second) != 16 {
+		t.Errorf("Ring(dqcjq, 2) = %d cells, want 16", len(second))
+	}
+	for _, hash := range second {
+		if slices.Contains(first, hash) || hash == "dqcjq" {
+			t.Errorf("Ring(dqcjq, 2) contains %s from an inner ring", hash)
+		}
+	}
+
+	// A ring wider than the grid visits each cell once: precision 1 has 8 columns.
+	res := slices.Collect(Ring("s", 5))
+	slices.Sort(res)
+	if len(res) != len(slices.Compact(slices.Clone(res))) {
+		t.Errorf("Ring(s, 5) = %v, contains duplicates", res)
+	}
+
+	for _, hash := range []string{"dqcja", ""} {
+		if res := slices.Collect(Ring(hash, 1)); len(res) != 0 {
+			t.Errorf("Ring(%s, 1) = %v, want empty", hash, res)
+		}
+	}
+}
+
+func TestRingInt(t *testing.T) {
+	for _, c := range neighborsCases {
+		want := make([]uint64, len(c.neighbors))
+		for i, n := range c.neighbors {
+			want[i] = EncodeStrToInt(n)
+		}
+		if res := slices.Collect(RingInt(EncodeStrToInt(c.hash), len(c.hash)*5, 1)); !slices.Equal(res, want) {
+			t.Errorf("RingInt(%s, 1) = %v, want %v", c.hash, res, want)
+		}
+	}
+
+	// Odd bit precisions have one more column than rows.
+	hash := EncodeIntPrecision(testLat, testLng, 33)
+	if res := slices.Collect(RingInt(hash, 33, 3)); len(res) != 24 {
+		t.Errorf("RingInt = %d cells, want 24", len(res))
+	}
+}
+
+func TestDisk(t *testing.T) {
+	res := slices.Collect(Disk("dqcjq", 2))
+	if len(res) != 25 || res[0] != "dqcjq" {
+		t.Errorf("Disk(dqcjq, 2) = %v, want 25 cells starting with dqcjq", res)
+	}
+
+	want := slices.Concat(slices.Collect(Ring("dqcjq", 0)), slices.Collect(Ring("dqcjq", 1)), slices.Collect(Ring("dqcjq", 2)))
+	if !slices.Equal(res, want) {
+		t.Errorf("Disk(dqcjq, 2) = %v, want %v", res, want)
+	}
+
+	// A disk wider than the grid yields each cell once: precision 1 has 8 columns and 4 rows.
+	res = slices.Collect(Disk("s", 10))
+	if len(res) != 32 {
+		t.Errorf("Disk(s, 10) = %d cells, want 32", len(res))
+	}
+
+	ints := slices.Collect(DiskInt(EncodeStrToInt("dqcjq"), 25, 2))
+	for i := range ints {
+		if EncodeIntToStr(ints[i], 5) != want[i] {
+			t.Errorf("DiskInt[%d] = %s, want %s", i, EncodeIntToStr(ints[i], 5), want[i])
+		}
+	}
+
+	if res := slices.Collect(Disk("dqcjq", -1)); len(res) != 0 {
+		t.Errorf("Disk(dqcjq, -1) = %v, want empty", res)
+	}
+}
+
+func TestRingHugeK(t *testing.T) {
+	// A huge k returns without walking a perimeter of 8k cells, which would not finish for math.MaxInt.
+	if res := slices.Collect(Ring("d", 5e7)); len(res) != 0 {
+		t.Errorf("Ring(d, 5e7) = %v, want empty", res)
+	}
+	if res := slices.Collect(RingInt(EncodeInt(testLat, testLng), 64, math.MaxInt)); len(res) != 0 {
+		t.Errorf("RingInt(64, MaxInt) = %v, want empty", res)
+	}
+
+	// The rings of a disk partition the grid, so a huge k yields every cell exactly once.
+	for bits := 1; bits <= 12; bits++ {
+		hash := EncodeIntPrecision(testLat, testLng, bits)
+		seen := map[uint64]bool{}
+		for k := 0; k <= gridRingMax(bits)+2; k++ {
+			for n := range RingInt(hash, bits, k) {
+				if seen[n] {
+					t.Errorf("RingInt(%d bits, %d) = %x from an inner ring", bits, k, n)
+				}
+				seen[n] = true
+			}
+		}
+		if len(seen) != 1<<bits {
+			t.Errorf("RingInt(%d bits) = %d cells, want %d", bits, len(seen), 1<<bits)
+		}
+
+		res := slices.Collect(DiskInt(hash, bits, 5e7))
+		if len(res) != 1<<bits {
+			t.Errorf("DiskInt(%d bits, 5e7) = %d cells, want %d", bits, len(res), 1<<bits)
+		}
+		for _, n := range res {
+			if !seen[n] {
+				t.Errorf("DiskInt(%d bits, 5e7) = %x not in a ring", bits, n)
+			}
+			delete(seen, n)
+		}
+	}
+}
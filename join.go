@@ -0,0 +1,203 @@
+package geohash
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// joinChunk is the number of points of the first dataset a worker takes at a time.
+	joinChunk = 1024
+
+	// joinBatch is the number of pairs a worker collects before sending them to the consumer.
+	joinBatch = 1024
+)
+
+// JoinPair is a pair of points within the distance of a Join, identified by their indexes in the first and second datasets.
+type JoinPair struct {
+	A, B   int
+	Meters float64
+}
+
+// JoinOptions configures Join.
+type JoinOptions struct {
+	// Workers is the number of goroutines searching for pairs. Defaults to runtime.GOMAXPROCS.
+	Workers int
+}
+
+// Join returns an iterator over every pair of points from a and b within meters of each other by great circle distance.
+// The points of b are indexed by geohash cell, at the finest bit precision whose cells are at least meters tall,
+// and sorted so each row of cells is contiguous. Each point of a then searches the rows above and below its own,
+// and as many columns either side as meters spans at its latitude, so pairs across cell borders and the antimeridian are found.
+//
+// Points of a are searched in chunks by parallel workers, so pairs are not ordered across chunks.
+// Memory is bounded by the index of b, 16 bytes per point, and a batch of pairs per worker waiting to be consumed;
+// pairs are never collected, so index the smaller dataset as b. Stopping the iteration early stops the workers.
+func Join(a, b []Point, meters float64, opts JoinOptions) iter.Seq[JoinPair] {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return func(yield func(JoinPair) bool) {
+		if meters < 0 || len(a) == 0 || len(b) == 0 {
+			return
+		}
+		x := newJoinIndex(b, meters)
+
+		batches := make(chan []JoinPair, workers)
+		done := make(chan struct{})
+		var next atomic.Int64
+		var wg sync.WaitGroup
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				batch := make([]JoinPair, 0, joinBatch)
+				send := func() bool {
+					select {
+					case batches <- batch:
+						batch = make([]JoinPair, 0, joinBatch)
+						return true
+					case <-done:
+						return false
+					}
+				}
+
+				for {
+					start := int(next.Add(joinChunk)) - joinChunk
+					if start >= len(a) {
+						break
+					}
+					for i := start; i < min(start+joinChunk, len(a)); i++ {
+						ok := x.search(a[i], meters, func(j int, d float64) bool {
+							batch = append(batch, JoinPair{A: i, B: j, Meters: d})
+							return len(batch) < joinBatch || send()
+						})
+						if !ok {
+							return
+						}
+					}
+				}
+				if len(batch) > 0 {
+					send()
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(batches)
+		}()
+
+		// Stopping early closes done, which unblocks workers waiting to send, then waits for them to exit.
+		defer func() {
+			close(done)
+			for range batches {
+			}
+		}()
+		for batch := range batches {
+			for _, pair := range batch {
+				if !yield(pair) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// joinIndex holds the points of a dataset sorted by the row and column of their cells.
+type joinIndex struct {
+	points           []Point
+	latBits, lngBits int
+	entries          []joinEntry
+}
+
+// joinEntry is a point of a joinIndex, keyed by its row followed by its column.
+type joinEntry struct {
+	key uint64
+	i   int
+}
+
+// newJoinIndex indexes points in cells at least meters tall.
+func newJoinIndex(points []Point, meters float64) *joinIndex {
+	bits := bitsMin
+	for b := bitsMax; b > bitsMin; b-- {
+		latBits, _ := gridBits(b)
+		if 2*latMax/math.Exp2(float64(latBits))*metersPerDegree >= meters {
+			bits = b
+			break
+		}
+	}
+
+	x := &joinIndex{points: points, entries: make([]joinEntry, len(points))}
+	x.latBits, x.lngBits = gridBits(bits)
+	for i, p := range points {
+		row, col := gridCell(encodeIntClamp(p.Lat, p.Lng, bits), bits)
+		x.entries[i] = joinEntry{key: x.key(uint64(row), uint64(col)), i: i}
+	}
+	slices.SortFunc(x.entries, func(a, b joinEntry) int {
+		return cmp.Compare(a.key, b.key)
+	})
+
+	return x
+}
+
+// key returns the index key of a row and column.
+func (x *joinIndex) key(row, col uint64) uint64 {
+	return row<<x.lngBits | col
+}
+
+// search calls fn with the index and distance of every point within meters of p.
+// Returns false when fn returns false.
+func (x *joinIndex) search(p Point, meters float64, fn func(int, float64) bool) bool {
+	rows, cols := int64(1)<<x.latBits, int64(1)<<x.lngBits
+	bits := x.latBits + x.lngBits
+	row, col := gridCell(encodeIntClamp(p.Lat, p.Lng, bits), bits)
+
+	// A path of length meters from p stays below the latitude meters poleward of p, where columns are narrowest,
+	// so the longitude it spans is at most meters over the width of a degree of longitude at that latitude.
+	k := cols / 2
+	poleward := math.Abs(p.Lat) + meters/metersPerDegree
+	if poleward < latMax {
+		span := meters / (metersPerDegree * math.Cos(poleward*math.Pi/180))
+		k = min(k, int64(math.Ceil(span/(2*lngMax/float64(cols)))))
+	}
+
+	// Cells are at least meters tall, so points within meters are at most one row away.
+	for r := max(0, int64(row)-1); r <= min(rows-1, int64(row)+1); r++ {
+		var ranges [2][2]int64
+		n := 1
+		switch lo, hi := int64(col)-k, int64(col)+k; {
+		case 2*k+1 >= cols:
+			ranges[0] = [2]int64{0, cols - 1}
+		case lo < 0:
+			ranges[0], ranges[1], n = [2]int64{0, hi}, [2]int64{lo + cols, cols - 1}, 2
+		case hi >= cols:
+			ranges[0], ranges[1], n = [2]int64{lo, cols - 1}, [2]int64{0, hi - cols}, 2
+		default:
+			ranges[0] = [2]int64{lo, hi}
+		}
+
+		for _, c := range ranges[:n] {
+			first, last := x.key(uint64(r), uint64(c[0])), x.key(uint64(r), uint64(c[1]))
+			j := sort.Search(len(x.entries), func(j int) bool { return x.entries[j].key >= first })
+			for ; j < len(x.entries) && x.entries[j].key <= last; j++ {
+				i := x.entries[j].i
+				if d := haversine(p, x.points[i]); d <= meters && !fn(i, d) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
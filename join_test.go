@@ -0,0 +1,102 @@
+package geohash
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// bruteJoin returns every pair of points within meters by comparing all pairs.
+func bruteJoin(a, b []Point, meters float64) []JoinPair {
+	pairs := []JoinPair{}
+	for i := range a {
+		for j := range b {
+			if d := haversine(a[i], b[j]); d <= meters {
+				pairs = append(pairs, JoinPair{A: i, B: j, Meters: d})
+			}
+		}
+	}
+	return pairs
+}
+
+func sortPairs(pairs []JoinPair) []JoinPair {
+	slices.SortFunc(pairs, func(x, y JoinPair) int {
+		if x.A != y.A {
+			return cmp.Compare(x.A, y.A)
+		}
+		return cmp.Compare(x.B, y.B)
+	})
+	return pairs
+}
+
+func TestJoin(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func(n int, lat, lng, spread float64) []Point {
+		points := make([]Point, n)
+		for i := range points {
+			points[i] = Point{Lat: lat + (r.Float64()*2-1)*spread, Lng: lng + (r.Float64()*2-1)*spread}
+			points[i].Lat = max(-90, min(90, points[i].Lat))
+			points[i].Lng = wrapLng(points[i].Lng)
+		}
+		return points
+	}
+
+	tests := []struct {
+		name   string
+		a, b   []Point
+		meters float64
+	}{
+		{"city", random(3000, 40.7, -74, 0.2), random(500, 40.7, -74, 0.2), 500},
+		{"antimeridian", random(1000, -16.5, 180, 0.5), random(1000, -16.5, 180, 0.5), 2000},
+		{"polar", random(1000, 89, 0, 1), random(1000, 89, 0, 1), 10000},
+		{"wide", random(300, 0, 0, 90), random(300, 0, 0, 90), 3000000},
+		{"exact", []Point{{1, 2}, {3, 4}}, []Point{{3, 4}, {1, 2}, {1, 2.0001}}, 0},
+	}
+
+	for _, test := range tests {
+		want := bruteJoin(test.a, test.b, test.meters)
+		for _, workers := range []int{1, 4} {
+			res := sortPairs(slices.Collect(Join(test.a, test.b, test.meters, JoinOptions{Workers: workers})))
+			if !slices.Equal(res, want) {
+				t.Errorf("Join(%s, %d workers) = %d pairs, want %d", test.name, workers, len(res), len(want))
+			}
+		}
+	}
+
+	if n := len(slices.Collect(Join(tests[0].a, tests[0].b, -1, JoinOptions{}))); n != 0 {
+		t.Errorf("Join = %d pairs, want 0 for a negative distance", n)
+	}
+}
+
+func TestJoinMaxEdge(t *testing.T) {
+	a := []Point{{90, 180}, {45, 180}}
+	b := []Point{{89.99999, 179.99999}, {45, 179.99999}}
+
+	res := sortPairs(slices.Collect(Join(a, b, 10, JoinOptions{})))
+	if want := bruteJoin(a, b, 10); !slices.Equal(res, want) || len(want) != 2 {
+		t.Errorf("Join = %+v, want %+v", res, want)
+	}
+}
+
+func TestJoinStop(t *testing.T) {
+	points := testPoints(5000, 1)
+
+	n := 0
+	for range Join(points, points, 10000, JoinOptions{Workers: 4}) {
+		if n++; n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Join = %d pairs, want 10", n)
+	}
+}
+
+func BenchmarkJoin(b *testing.B) {
+	a, c := testPoints(100000, 1), testPoints(10000, 2)
+	for n := 0; n < b.N; n++ {
+		for range Join(a, c, 200, JoinOptions{}) {
+		}
+	}
+}
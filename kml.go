@@ -0,0 +1,164 @@
+package geohash
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// kmlPalette is cycled by precision to color cells written by WriteCell.
+// Entries are rrggbb and are reordered by kmlPrecisionColor as KML colors are aabbggrr.
+var kmlPalette = []string{
+	"1f77b4", "ff7f0e", "2ca02c", "d62728", "9467bd",
+	"8c564b", "e377c2", "7f7f7f", "bcbd22", "17becf",
+}
+
+// kmlFillAlpha is the alpha applied to cell polygons so overlapping cells and underlying imagery remain visible.
+const kmlFillAlpha = "66"
+
+// KMLWriter streams geohash cells to KML placemarks for visual inspection in desktop GIS tools such as Google Earth.
+// Cells are grouped in a folder per precision.
+// A folder is opened when the first cell of a precision is written and closed when a cell of a different precision follows.
+// Coverings and neighbor rings are naturally grouped by precision, but mixed precision input should be sorted by length first to avoid repeated folders.
+// Nothing is retained between cells, so large coverings may be written one cell at a time without being built in memory.
+// The first error encountered is retained and returned by every subsequent call.
+type KMLWriter struct {
+	w         io.Writer
+	precision int
+	err       error
+}
+
+// NewKMLWriter writes the KML document header and per precision styles to w.
+// The name is used as the document name displayed by GIS tools.
+func NewKMLWriter(w io.Writer, name string) *KMLWriter {
+	k := &KMLWriter{w: w}
+
+	k.printf("%s", xml.Header)
+	k.printf("<kml xmlns=\"http://www.opengis.net/kml/2.2\">\n<Document>\n<name>%s</name>\n", kmlEscape(name))
+	for p := precisionMin; p <= precisionHigh; p++ {
+		k.printf("<Style id=\"precision%d\">%s</Style>\n", p, kmlStyle(kmlPrecisionColor(p)))
+	}
+
+	return k
+}
+
+// WriteCell writes the geohash cell as a placemark colored by its precision.
+// ErrInvalidHash is returned for empty or invalid geohash strings, and nothing is written.
+func (k *KMLWriter) WriteCell(hash string) error {
+	hash = truncateHigh(hash)
+	if err := validHash(hash); err != nil {
+		return err
+	}
+	k.folder(len(hash))
+	k.printf("<Placemark>\n<name>%s</name>\n<styleUrl>#precision%d</styleUrl>\n", kmlEscape(hash), len(hash))
+	k.polygon(hash)
+	k.printf("</Placemark>\n")
+	return k.err
+}
+
+// WriteCellValue writes the geohash cell as a placemark colored by a caller supplied value.
+// The value is clamped to [0, 1] and mapped to a gradient from blue (0) to red (1).
+// The value is also written as the placemark description.
+// ErrInvalidHash is returned for empty or invalid geohash strings, and nothing is written.
+func (k *KMLWriter) WriteCellValue(hash string, v float64) error {
+	hash = truncateHigh(hash)
+	if err := validHash(hash); err != nil {
+		return err
+	}
+	k.folder(len(hash))
+	k.printf("<Placemark>\n<name>%s</name>\n<description>%g</description>\n", kmlEscape(hash), v)
+	k.printf("<Style>%s</Style>\n", kmlStyle(kmlValueColor(v)))
+	k.polygon(hash)
+	k.printf("</Placemark>\n")
+	return k.err
+}
+
+// WriteNeighbors writes the neighbor ring surrounding the geohash cell as placemarks colored by precision.
+// The cell itself is not written. ErrInvalidHash is returned for empty or invalid geohash strings.
+func (k *KMLWriter) WriteNeighbors(hash string) error {
+	if err := validHash(truncateHigh(hash)); err != nil {
+		return err
+	}
+	for _, n := range Neighbors(hash) {
+		if err := k.WriteCell(n); err != nil {
+			return err
+		}
+	}
+	return k.err
+}
+
+// Close closes any open folder and the KML document.
+// The underlying io.Writer is not closed.
+func (k *KMLWriter) Close() error {
+	k.folder(0)
+	k.printf("</Document>\n</kml>\n")
+	return k.err
+}
+
+// folder closes the current precision folder and opens a new one if the precision has changed.
+// A precision of 0 closes the current folder without opening another.
+func (k *KMLWriter) folder(precision int) {
+	if precision == k.precision {
+		return
+	}
+	if k.precision != 0 {
+		k.printf("</Folder>\n")
+	}
+	if precision != 0 {
+		k.printf("<Folder>\n<name>precision %d</name>\n", precision)
+	}
+	k.precision = precision
+}
+
+// polygon writes the outer boundary of the cell decoded by decodeBox.
+// KML coordinates are lng,lat,altitude and the ring must be closed by repeating the first coordinate.
+func (k *KMLWriter) polygon(hash string) {
+	b := decodeBox(hash)
+	k.printf("<Polygon><outerBoundaryIs><LinearRing><coordinates>")
+	k.printf("%g,%g,0 %g,%g,0 %g,%g,0 %g,%g,0 %g,%g,0",
+		b.MinLng, b.MinLat,
+		b.MaxLng, b.MinLat,
+		b.MaxLng, b.MaxLat,
+		b.MinLng, b.MaxLat,
+		b.MinLng, b.MinLat,
+	)
+	k.printf("</coordinates></LinearRing></outerBoundaryIs></Polygon>\n")
+}
+
+// printf writes to the underlying io.Writer unless a previous write failed.
+func (k *KMLWriter) printf(format string, a ...any) {
+	if k.err != nil {
+		return
+	}
+	_, k.err = fmt.Fprintf(k.w, format, a...)
+}
+
+// kmlStyle returns the line and polygon style elements for a color in bbggrr order.
+func kmlStyle(bgr string) string {
+	return fmt.Sprintf("<LineStyle><color>ff%s</color><width>1</width></LineStyle><PolyStyle><color>%s%s</color></PolyStyle>", bgr, kmlFillAlpha, bgr)
+}
+
+// kmlPrecisionColor returns the palette color for precision in bbggrr order.
+func kmlPrecisionColor(precision int) string {
+	rgb := kmlPalette[(precision-1)%len(kmlPalette)]
+	return rgb[4:6] + rgb[2:4] + rgb[0:2]
+}
+
+// kmlValueColor returns a color in bbggrr order interpolated from blue to red by v.
+func kmlValueColor(v float64) string {
+	if math.IsNaN(v) {
+		v = 0
+	}
+	v = math.Max(0, math.Min(1, v))
+	r := uint8(math.Round(255 * v))
+	return fmt.Sprintf("%02x00%02x", 255-r, r)
+}
+
+// kmlEscape escapes s for use as KML element text.
+func kmlEscape(s string) string {
+	b := strings.Builder{}
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKMLWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+	k := NewKMLWriter(&buf, "test & cells")
+
+	k.WriteCell("dqcjq")
+	k.WriteNeighbors("dqcjq")
+	k.WriteCellValue("dqcj", 0.5)
+	for _, c := range testCases {
+		k.WriteCell(c.hash)
+	}
+	if err := k.Close(); err != nil {
+		t.Fatalf("Close = %s", err.Error())
+	}
+
+	doc := struct {
+		Document struct {
+			Name    string `xml:"name"`
+			Folders []struct {
+				Name       string `xml:"name"`
+				Placemarks []struct {
+					Name        string `xml:"name"`
+					Coordinates string `xml:"Polygon>outerBoundaryIs>LinearRing>coordinates"`
+				} `xml:"Placemark"`
+			} `xml:"Folder"`
+		}
+	}{}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Unmarshal = %s", err.Error())
+	}
+
+	if doc.Document.Name != "test & cells" {
+		t.Errorf("Name = %s, want %s", doc.Document.Name, "test & cells")
+	}
+
+	want := []struct {
+		name       string
+		placemarks int
+	}{
+		{"precision 5", 9},
+		{"precision 4", 1},
+		{"precision 12", len(testCases)},
+	}
+	if len(doc.Document.Folders) != len(want) {
+		t.Fatalf("Folders = %d, want %d", len(doc.Document.Folders), len(want))
+	}
+	for i, w := range want {
+		f := doc.Document.Folders[i]
+		if f.Name != w.name || len(f.Placemarks) != w.placemarks {
+			t.Errorf("Folder = %s with %d placemarks, want %s with %d", f.Name, len(f.Placemarks), w.name, w.placemarks)
+		}
+	}
+
+	p := doc.Document.Folders[1].Placemarks[0]
+	if coords := strings.Fields(p.Coordinates); len(coords) != 5 || coords[0] != coords[4] {
+		t.Errorf("Coordinates = %s, want closed ring of 5", p.Coordinates)
+	}
+}
+
+func TestKMLWriterInvalid(t *testing.T) {
+	buf := bytes.Buffer{}
+	k := NewKMLWriter(&buf, "invalid")
+	n := buf.Len()
+
+	for _, hash := range []string{"", "dqcja"} {
+		if err := k.WriteCell(hash); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("WriteCell(%q) = %v, want %v", hash, err, ErrInvalidHash)
+		}
+		if err := k.WriteCellValue(hash, 0.5); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("WriteCellValue(%q) = %v, want %v", hash, err, ErrInvalidHash)
+		}
+		if err := k.WriteNeighbors(hash); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("WriteNeighbors(%q) = %v, want %v", hash, err, ErrInvalidHash)
+		}
+	}
+	if buf.Len() != n {
+		t.Errorf("wrote %q for invalid cells", buf.Bytes()[n:])
+	}
+
+	// Invalid cells are not retained as write errors.
+	if err := k.WriteCell("dqcjq"); err != nil {
+		t.Errorf("WriteCell = %s", err.Error())
+	}
+}
+
+func TestKMLValueColor(t *testing.T) {
+	cases := []struct {
+		v   float64
+		bgr string
+	}{
+		{-1, "ff0000"},
+		{0, "ff0000"},
+		{1, "0000ff"},
+		{2, "0000ff"},
+	}
+	for _, c := range cases {
+		if res := kmlValueColor(c.v); res != c.bgr {
+			t.Errorf("kmlValueColor = %s, want %s", res, c.bgr)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package maidenhead implements Maidenhead locators (QTH locators or grid squares) and conversion to and from geohash strings.
+// Like the geohash package, it is written to document the algorithm as much as to be used.
+// See https://en.wikipedia.org/wiki/Maidenhead_Locator_System for a description of the system.
+package maidenhead
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bbailey1024/geohash"
+)
+
+const (
+	minPairs = 1
+	maxPairs = 5
+	latMax   = 90
+	lngMax   = 180
+)
+
+// ErrInvalidLocator is returned for locators that are not valid Maidenhead locators.
+var ErrInvalidLocator = errors.New("maidenhead: invalid locator")
+
+// divisions are the number of columns and rows each pair of characters divides the previous cell into.
+// The first pair (field) is a letter A to R, followed by alternating digit pairs (square) and letter pairs A to X (subsquare).
+var divisions = [maxPairs]int{18, 10, 24, 10, 24}
+
+// Encode returns the Maidenhead locator of lat, lng with the provided number of character pairs.
+// Acceptable pair values are 1 to 5, a precision of 20 by 10 degrees to 3 by 1.5 arc seconds.
+// Fields are uppercase and subsquares are lowercase, following common usage.
+// Latitude is clipped to ±90 and longitude is normalized to [-180, 180).
+func Encode(lat, lng float64, pairs int) string {
+	pairs = max(minPairs, min(maxPairs, pairs))
+
+	// Offset the coordinates to be positive, then repeatedly select the column and row containing them.
+	lat = math.Max(-latMax, math.Min(latMax, lat)) + latMax
+	lng = math.Mod(lng+lngMax, 2*lngMax)
+	if lng < 0 {
+		lng += 2 * lngMax
+	}
+
+	h, w := float64(2*latMax), float64(2*lngMax)
+	loc := make([]byte, 2*pairs)
+	for i := 0; i < pairs; i++ {
+		div := divisions[i]
+		h /= float64(div)
+		w /= float64(div)
+
+		col := max(0, min(div-1, int(lng/w)))
+		row := max(0, min(div-1, int(lat/h)))
+		lng -= float64(col) * w
+		lat -= float64(row) * h
+
+		loc[2*i], loc[2*i+1] = pairBase(i)+byte(col), pairBase(i)+byte(row)
+	}
+	return string(loc)
+}
+
+// Decode returns the bounding box of a locator of 1 to 5 character pairs. Letters are case insensitive.
+// ErrInvalidLocator is returned for locators of an odd or invalid length, or characters outside the range of their pair.
+func Decode(locator string) (geohash.Box, error) {
+	if len(locator) < 2*minPairs || len(locator) > 2*maxPairs || len(locator)%2 != 0 {
+		return geohash.Box{}, fmt.Errorf("%w: %q has %d characters", ErrInvalidLocator, locator, len(locator))
+	}
+
+	var lat, lng float64
+	h, w := float64(2*latMax), float64(2*lngMax)
+	for i := 0; i < len(locator)/2; i++ {
+		div := divisions[i]
+		h /= float64(div)
+		w /= float64(div)
+
+		col, ok := pairIndex(i, locator[2*i])
+		if !ok {
+			return geohash.Box{}, fmt.Errorf("%w: %q at index %d", ErrInvalidLocator, locator[2*i], 2*i)
+		}
+		row, ok := pairIndex(i, locator[2*i+1])
+		if !ok {
+			return geohash.Box{}, fmt.Errorf("%w: %q at index %d", ErrInvalidLocator, locator[2*i+1], 2*i+1)
+		}
+
+		lng += float64(col) * w
+		lat += float64(row) * h
+	}
+
+	return geohash.Box{
+		MinLat: lat - latMax,
+		MaxLat: lat + h - latMax,
+		MinLng: lng - lngMax,
+		MaxLng: lng + w - lngMax,
+	}, nil
+}
+
+// Geohash returns the geohash string of the center of a locator.
+// The geohash precision is the one closest in size to the locator, as returned by geohash.PrecisionForSize.
+// ErrInvalidLocator is returned for invalid locators.
+func Geohash(locator string) (string, error) {
+	box, err := Decode(locator)
+	if err != nil {
+		return "", err
+	}
+	lat, lng := box.Center()
+	return geohash.EncodePrecision(lat, lng, geohash.PrecisionForSize(box.Height(), box.Width())), nil
+}
+
+// FromGeohash returns the locator of the center of a geohash cell of 1 to 12 characters.
+// The number of pairs is the one whose locator is closest in size to the cell, compared by area as geohash.PrecisionForSize does.
+// geohash.ErrInvalidHash is returned for invalid geohash strings.
+func FromGeohash(hash string) (string, error) {
+	if _, err := geohash.ParseHash(hash); err != nil {
+		return "", err
+	}
+	box := geohash.DecodeBox(hash)
+	area := box.Height() * box.Width()
+
+	best, bestDiff := minPairs, math.Inf(1)
+	h, w := float64(2*latMax), float64(2*lngMax)
+	for i := 0; i < maxPairs; i++ {
+		h /= float64(divisions[i])
+		w /= float64(divisions[i])
+		if diff := math.Abs(math.Log(h * w / area)); diff < bestDiff {
+			best, bestDiff = i+1, diff
+		}
+	}
+
+	lat, lng := box.Center()
+	return Encode(lat, lng, best), nil
+}
+
+// pairBase returns the character of index 0 for pair i: 'A' for fields, '0' for squares and 'a' for subsquares.
+func pairBase(i int) byte {
+	switch {
+	case i == 0:
+		return 'A'
+	case i%2 == 1:
+		return '0'
+	default:
+		return 'a'
+	}
+}
+
+// pairIndex returns the index of character c in pair i, and false if c is outside the range of the pair.
+func pairIndex(i int, c byte) (int, bool) {
+	if i%2 == 0 {
+		c |= 0x20 // lowercase
+		if c < 'a' {
+			return 0, false
+		}
+		idx := int(c - 'a')
+		return idx, idx < divisions[i]
+	}
+	if c < '0' {
+		return 0, false
+	}
+	idx := int(c - '0')
+	return idx, idx < divisions[i]
+}
@@ -0,0 +1,112 @@
+package maidenhead
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/bbailey1024/geohash"
+)
+
+// readCSV returns the records of a test data file, skipping comment lines beginning with #.
+func readCSV(t *testing.T, name string) [][]string {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Open = %s", err.Error())
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll = %s", err.Error())
+	}
+	return records
+}
+
+func TestEncode(t *testing.T) {
+	for _, r := range readCSV(t, "locators.csv") {
+		lat, _ := strconv.ParseFloat(r[0], 64)
+		lng, _ := strconv.ParseFloat(r[1], 64)
+		pairs, _ := strconv.Atoi(r[2])
+
+		if res := Encode(lat, lng, pairs); res != r[3] {
+			t.Errorf("Encode(%f, %f, %d) = %s, want %s", lat, lng, pairs, res, r[3])
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, r := range readCSV(t, "locators.csv") {
+		box, err := Decode(r[3])
+		if err != nil {
+			t.Fatalf("Decode = %s", err.Error())
+		}
+
+		lat, lng := box.Center()
+		if res := Encode(lat, lng, len(r[3])/2); res != r[3] {
+			t.Errorf("Encode(Decode(%s).Center()) = %s", r[3], res)
+		}
+	}
+
+	box, _ := Decode("jn58TD")
+	if want, _ := Decode("JN58td"); box != want {
+		t.Errorf("Decode(jn58TD) = %+v, want %+v", box, want)
+	}
+
+	for _, loc := range []string{"", "J", "JN5", "SN58", "JNA8", "JN58ty", "JN58td5", "JN58td00aa00"} {
+		if _, err := Decode(loc); !errors.Is(err, ErrInvalidLocator) {
+			t.Errorf("Decode(%s) = %v, want %v", loc, err, ErrInvalidLocator)
+		}
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	testCases := []struct {
+		locator, hash string
+	}{
+		{"JJ", "s0"},
+		{"JN58td", "u283b"},
+		{"FN31pr", "drkm3"},
+	}
+
+	for _, c := range testCases {
+		res, err := Geohash(c.locator)
+		if err != nil {
+			t.Fatalf("Geohash = %s", err.Error())
+		}
+		if res != c.hash {
+			t.Errorf("Geohash(%s) = %s, want %s", c.locator, res, c.hash)
+		}
+	}
+}
+
+func TestFromGeohash(t *testing.T) {
+	testCases := []struct {
+		hash, locator string
+	}{
+		{"u", "KP"},
+		{"u28", "JN58"},
+		{"dqcjq", "FM18lv"},
+		{"dqcjqcp", "FM18lv55"},
+	}
+
+	for _, c := range testCases {
+		res, err := FromGeohash(c.hash)
+		if err != nil {
+			t.Fatalf("FromGeohash = %s", err.Error())
+		}
+		if res != c.locator {
+			t.Errorf("FromGeohash(%s) = %s, want %s", c.hash, res, c.locator)
+		}
+	}
+
+	if _, err := FromGeohash("dqcja"); !errors.Is(err, geohash.ErrInvalidHash) {
+		t.Errorf("FromGeohash = %v, want %v", err, geohash.ErrInvalidHash)
+	}
+}
@@ -0,0 +1,329 @@
+// Package mgrs implements Military Grid Reference System (MGRS) references and conversion to and from geohash strings.
+// Like the geohash package, it is written to document the algorithm as much as to be used.
+// References are computed from Universal Transverse Mercator (UTM) coordinates on the WGS84 ellipsoid.
+// The polar regions, which MGRS covers with the Universal Polar Stereographic projection, are not supported.
+// See https://earth-info.nga.mil/index.php?dir=coordsys&action=coordsys#mgrs for the NGA documentation.
+package mgrs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/bbailey1024/geohash"
+)
+
+const (
+	// WGS84 ellipsoid and UTM projection parameters.
+	a  = 6378137.0
+	f  = 1 / 298.257223563
+	e2 = f * (2 - f)
+	k0 = 0.9996
+
+	falseEasting  = 500000
+	falseNorthing = 10000000
+
+	latMin  = -80
+	latMax  = 84
+	lngMax  = 180
+	bandMin = -80
+	band    = 8
+
+	squareSize = 100000
+	rowCycle   = 2000000
+	maxDigits  = 5
+
+	// metersPerDegree is the approximate length of a degree of latitude, used to compare square sizes to geohash cells.
+	metersPerDegree = 111320
+)
+
+var (
+	// ErrInvalidReference is returned for strings that are not valid MGRS references.
+	ErrInvalidReference = errors.New("mgrs: invalid reference")
+
+	// ErrPolar is returned for coordinates beyond the UTM latitude limits of 80°S and 84°N.
+	ErrPolar = errors.New("mgrs: polar regions not supported")
+
+	// ErrInvalidLongitude is returned for longitudes that are NaN or infinite, which have no zone.
+	ErrInvalidLongitude = errors.New("mgrs: invalid longitude")
+)
+
+const (
+	// bands are the latitude band letters of 8 degrees from 80°S, where X is extended to 84°N.
+	bands = "CDEFGHJKLMNPQRSTUVWX"
+
+	// rowLetters are the 100km row letters, which repeat every 2,000km of northing.
+	rowLetters = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// colLetters are the 100km column letters, which repeat every 3 zones.
+var colLetters = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// Encode returns the MGRS reference of lat, lng with the provided number of digits per axis.
+// Acceptable digit values are 0 (100km) to 5 (1m). Values are truncated, so the reference is the square containing the point.
+// The zone is written without a leading zero, as in 4QFJ12345678.
+// Longitude is normalized to [-180, 180). ErrPolar is returned for latitudes beyond 80°S or 84°N,
+// and ErrInvalidLongitude for a NaN or infinite longitude.
+func Encode(lat, lng float64, digits int) (string, error) {
+	if math.IsNaN(lat) || lat < latMin || lat > latMax {
+		return "", fmt.Errorf("%w: latitude %f", ErrPolar, lat)
+	}
+	if math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return "", fmt.Errorf("%w: %f", ErrInvalidLongitude, lng)
+	}
+	digits = max(0, min(maxDigits, digits))
+	lng = normalizeLng(lng)
+
+	zone := zoneOf(lat, lng)
+	easting, northing := toUTM(lat, lng, zone)
+
+	col, row := squareLetters(zone, easting, northing)
+	div := math.Pow10(maxDigits - digits)
+	e := int(math.Mod(easting, squareSize) / div)
+	n := int(math.Mod(northing, squareSize) / div)
+
+	ref := strconv.Itoa(zone) + string(bands[bandOf(lat)]) + string(col) + string(row)
+	if digits > 0 {
+		ref += fmt.Sprintf("%0*d%0*d", digits, e, digits, n)
+	}
+	return ref, nil
+}
+
+// Decode returns the lat, lng coordinates of the southwest corner of the square described by an MGRS reference,
+// and the size of the square in meters. Letters are case insensitive and the zone may have a leading zero.
+// ErrInvalidReference is returned for invalid references, including letters that do not exist in the zone.
+func Decode(ref string) (float64, float64, float64, error) {
+	zone, lat, easting, northing, size, err := parse(ref)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	lat, lng := fromUTM(easting, northing, zone, lat >= 0)
+	return lat, lng, size, nil
+}
+
+// Geohash returns the geohash string of the center of the square described by an MGRS reference.
+// The geohash precision is the one closest in size to the square, as returned by geohash.PrecisionForSize.
+// ErrInvalidReference is returned for invalid references.
+func Geohash(ref string) (string, error) {
+	zone, lat, easting, northing, size, err := parse(ref)
+	if err != nil {
+		return "", err
+	}
+	lat, lng := fromUTM(easting+size/2, northing+size/2, zone, lat >= 0)
+
+	h := size / metersPerDegree
+	w := h / math.Cos(lat*math.Pi/180)
+	return geohash.EncodePrecision(lat, lng, geohash.PrecisionForSize(h, w)), nil
+}
+
+// FromGeohash returns the MGRS reference of the center of a geohash cell of 1 to 12 characters.
+// The number of digits is the one whose square is closest in size to the cell, compared by area as geohash.PrecisionForSize does.
+// geohash.ErrInvalidHash is returned for invalid geohash strings, and ErrPolar for cells centered in the polar regions.
+func FromGeohash(hash string) (string, error) {
+	if _, err := geohash.ParseHash(hash); err != nil {
+		return "", err
+	}
+	box := geohash.DecodeBox(hash)
+	lat, lng := box.Center()
+
+	// The area of the cell in square meters, shrinking longitude with latitude.
+	area := box.Height() * metersPerDegree * box.Width() * metersPerDegree * math.Cos(lat*math.Pi/180)
+
+	best, bestDiff := 0, math.Inf(1)
+	for d := 0; d <= maxDigits; d++ {
+		size := squareSize / math.Pow10(d)
+		if diff := math.Abs(math.Log(size * size / area)); diff < bestDiff {
+			best, bestDiff = d, diff
+		}
+	}
+	return Encode(lat, lng, best)
+}
+
+// parse returns the zone, band min latitude, UTM easting and northing of the southwest corner, and the size in meters of a reference.
+// The 100km row letters repeat every 2,000km, so the northing is the first repetition at or north of the latitude band.
+func parse(ref string) (int, float64, float64, float64, float64, error) {
+	invalid := func(reason string) (int, float64, float64, float64, float64, error) {
+		return 0, 0, 0, 0, 0, fmt.Errorf("%w: %q %s", ErrInvalidReference, ref, reason)
+	}
+
+	i := 0
+	for i < len(ref) && i < 2 && ref[i] >= '0' && ref[i] <= '9' {
+		i++
+	}
+	zone, err := strconv.Atoi(ref[:i])
+	if err != nil || zone < 1 || zone > 60 {
+		return invalid("has an invalid zone")
+	}
+	if len(ref) < i+3 {
+		return invalid("is missing letters")
+	}
+
+	b := indexUpper(bands, ref[i])
+	col := indexUpper(colLetters[(zone-1)%3], ref[i+1])
+	row := indexUpper(rowLetters, ref[i+2])
+	if b < 0 || col < 0 || row < 0 {
+		return invalid("has an invalid letter")
+	}
+
+	digits := ref[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 2*maxDigits {
+		return invalid("has an invalid number of digits")
+	}
+	n := len(digits) / 2
+	size := squareSize / math.Pow10(n)
+
+	var e, north float64
+	if n > 0 {
+		ev, err1 := strconv.ParseUint(digits[:n], 10, 32)
+		nv, err2 := strconv.ParseUint(digits[n:], 10, 32)
+		if err1 != nil || err2 != nil {
+			return invalid("has invalid digits")
+		}
+		e, north = float64(ev)*size, float64(nv)*size
+	}
+
+	// Even zones offset the row letters by 5 (500km).
+	if zone%2 == 0 {
+		row = (row - 5 + len(rowLetters)) % len(rowLetters)
+	}
+
+	lat := float64(bandMin + b*band)
+	easting := float64(col+1)*squareSize + e
+	northing := float64(row)*squareSize + north
+
+	// The northing of the band's south edge on the central meridian, less a margin for the edges of the zone.
+	_, minNorthing := toUTM(lat, centralMeridian(zone), zone)
+	for northing < minNorthing-squareSize {
+		northing += rowCycle
+	}
+
+	return zone, lat, easting, northing, size, nil
+}
+
+// zoneOf returns the UTM zone of lat, lng, including the exceptions for southwest Norway and Svalbard.
+func zoneOf(lat, lng float64) int {
+	zone := int((lng+lngMax)/6) + 1
+
+	switch {
+	case lat >= 56 && lat < 64 && lng >= 3 && lng < 12:
+		return 32
+	case lat >= 72 && lng >= 0 && lng < 42:
+		switch {
+		case lng < 9:
+			return 31
+		case lng < 21:
+			return 33
+		case lng < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+	return min(zone, 60)
+}
+
+// bandOf returns the index of the latitude band of lat, where band X covers 72°N to 84°N.
+func bandOf(lat float64) int {
+	return max(0, min(len(bands)-1, int((lat-bandMin)/band)))
+}
+
+// centralMeridian returns the longitude of the central meridian of a zone.
+func centralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - lngMax + 3
+}
+
+// squareLetters returns the 100km column and row letters of a UTM easting and northing.
+// The column letters cycle through 3 sets of 8 letters, one set per zone.
+// The row letters cycle every 2,000km of northing and are offset by 5 in even zones.
+func squareLetters(zone int, easting, northing float64) (byte, byte) {
+	col := int(easting/squareSize) - 1
+	row := int(math.Mod(northing, rowCycle) / squareSize)
+	if zone%2 == 0 {
+		row += 5
+	}
+	return colLetters[(zone-1)%3][col], rowLetters[row%len(rowLetters)]
+}
+
+// toUTM returns the UTM easting and northing of lat, lng projected into a zone, using the series expansion in
+// Snyder, Map Projections: A Working Manual (USGS Professional Paper 1395), which is accurate to millimeters within a zone.
+// Southern latitudes include the false northing of 10,000km.
+func toUTM(lat, lng float64, zone int) (float64, float64) {
+	ep2 := e2 / (1 - e2)
+	phi := lat * math.Pi / 180
+	lam := (lng - centralMeridian(zone)) * math.Pi / 180
+
+	sin, cos, tan := math.Sin(phi), math.Cos(phi), math.Tan(phi)
+	n := a / math.Sqrt(1-e2*sin*sin)
+	t := tan * tan
+	c := ep2 * cos * cos
+	A := cos * lam
+
+	e4, e6 := e2*e2, e2*e2*e2
+	m := a * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+
+	easting := k0*n*(A+(1-t+c)*math.Pow(A, 3)/6+(5-18*t+t*t+72*c-58*ep2)*math.Pow(A, 5)/120) + falseEasting
+	northing := k0 * (m + n*tan*(A*A/2+(5-t+9*c+4*c*c)*math.Pow(A, 4)/24+(61-58*t+t*t+600*c-330*ep2)*math.Pow(A, 6)/720))
+	if lat < 0 {
+		northing += falseNorthing
+	}
+	return easting, northing
+}
+
+// fromUTM returns the lat, lng coordinates of a UTM easting and northing, the inverse of toUTM.
+func fromUTM(easting, northing float64, zone int, north bool) (float64, float64) {
+	ep2 := e2 / (1 - e2)
+	if !north {
+		northing -= falseNorthing
+	}
+
+	e4, e6 := e2*e2, e2*e2*e2
+	m := northing / k0
+	mu := m / (a * (1 - e2/4 - 3*e4/64 - 5*e6/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sin, cos, tan := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+	n1 := a / math.Sqrt(1-e2*sin*sin)
+	t1 := tan * tan
+	c1 := ep2 * cos * cos
+	r1 := a * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	d := (easting - falseEasting) / (n1 * k0)
+
+	phi := phi1 - (n1*tan/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+	lam := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cos
+
+	return phi * 180 / math.Pi, centralMeridian(zone) + lam*180/math.Pi
+}
+
+// indexUpper returns the index of c in s, uppercasing c, or -1 if it is not in s.
+func indexUpper(s string, c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeLng returns lng normalized to [-180, 180).
+func normalizeLng(lng float64) float64 {
+	lng = math.Mod(lng+lngMax, 2*lngMax)
+	if lng < 0 {
+		lng += 2 * lngMax
+	}
+	return lng - lngMax
+}
@@ -0,0 +1,149 @@
+package mgrs
+
+import (
+	"encoding/csv"
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/bbailey1024/geohash"
+)
+
+// readCSV returns the records of a test data file, skipping comment lines beginning with #.
+func readCSV(t *testing.T, name string) [][]string {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Open = %s", err.Error())
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll = %s", err.Error())
+	}
+	return records
+}
+
+func TestEncode(t *testing.T) {
+	for _, r := range readCSV(t, "references.csv") {
+		lat, _ := strconv.ParseFloat(r[0], 64)
+		lng, _ := strconv.ParseFloat(r[1], 64)
+		digits, _ := strconv.Atoi(r[2])
+
+		res, err := Encode(lat, lng, digits)
+		if err != nil {
+			t.Fatalf("Encode = %s", err.Error())
+		}
+		if res != r[3] {
+			t.Errorf("Encode(%f, %f, %d) = %s, want %s", lat, lng, digits, res, r[3])
+		}
+	}
+
+	for _, lat := range []float64{-80.1, 84.1, math.NaN()} {
+		if _, err := Encode(lat, 0, 5); !errors.Is(err, ErrPolar) {
+			t.Errorf("Encode(%f) = %v, want %v", lat, err, ErrPolar)
+		}
+	}
+
+	for _, lng := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := Encode(0, lng, 5); !errors.Is(err, ErrInvalidLongitude) {
+			t.Errorf("Encode(0, %f) = %v, want %v", lng, err, ErrInvalidLongitude)
+		}
+	}
+
+	// Longitudes outside [-180, 180) are normalized before the zone is computed.
+	for _, c := range [][2]float64{{180, -180}, {540, -180}, {-540, -180}, {370, 10}, {-190, 170}} {
+		want, _ := Encode(10, c[1], 5)
+		if res, err := Encode(10, c[0], 5); err != nil || res != want {
+			t.Errorf("Encode(10, %f) = %s, %v, want %s", c[0], res, err, want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, r := range readCSV(t, "references.csv") {
+		lat, lng, size, err := Decode(r[3])
+		if err != nil {
+			t.Fatalf("Decode = %s", err.Error())
+		}
+
+		digits, _ := strconv.Atoi(r[2])
+		if want := 100000 / math.Pow10(digits); size != want {
+			t.Errorf("Decode(%s) size = %f, want %f", r[3], size, want)
+		}
+
+		// The southwest corner may fall in the adjacent zone, so the center of the square is re-encoded instead.
+		// Squares at the limits of the grid may be centered beyond 84°N or the antimeridian.
+		zone, band, easting, northing, _, _ := parse(r[3])
+		clat, clng := fromUTM(easting+size/2, northing+size/2, zone, band >= 0)
+		if res, _ := Encode(clat, clng, digits); res != r[3] && clat <= latMax && clng < lngMax {
+			t.Errorf("Encode(Decode(%s)) = %s", r[3], res)
+		}
+		if lat >= clat || lng >= clng {
+			t.Errorf("Decode(%s) = %f, %f, want southwest of %f, %f", r[3], lat, lng, clat, clng)
+		}
+	}
+
+	lat, lng, _, _ := Decode("31naa6602100000")
+	if math.Abs(lat) > 1e-6 || math.Abs(lng) > 1e-4 {
+		t.Errorf("Decode(31naa6602100000) = %f, %f, want 0, 0", lat, lng)
+	}
+	if zlat, zlng, _, _ := Decode("04QFJ12345678"); zlat == 0 || zlng == 0 {
+		t.Errorf("Decode(04QFJ12345678) = %f, %f, want a leading zero zone", zlat, zlng)
+	}
+
+	for _, ref := range []string{"", "31", "0NAA", "61NAA", "31IAA", "31NJA", "31NAW", "31NAA1", "31NAA12345123456", "31NAA1x"} {
+		if _, _, _, err := Decode(ref); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Decode(%s) = %v, want %v", ref, err, ErrInvalidReference)
+		}
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	// The center of 33UXP04 is the Vienna example point.
+	res, err := Geohash("33UXP04")
+	if err != nil {
+		t.Fatalf("Geohash = %s", err.Error())
+	}
+	if want := geohash.EncodePrecision(48.24949, 16.41450, 4); res != want {
+		t.Errorf("Geohash(33UXP04) = %s, want %s", res, want)
+	}
+
+	if res, _ := Geohash("31NAA6602100000"); len(res) != 10 {
+		t.Errorf("Geohash(31NAA6602100000) = %s, want 10 characters", res)
+	}
+}
+
+func TestFromGeohash(t *testing.T) {
+	testCases := []struct {
+		hash, ref string
+	}{
+		{"u28", "32UQU"},
+		{"dqcjq", "18SUJ20"},
+		{"dqcjqcp", "18SUJ233074"},
+		{"dqcjqcpe", "18SUJ23380739"},
+	}
+
+	for _, c := range testCases {
+		res, err := FromGeohash(c.hash)
+		if err != nil {
+			t.Fatalf("FromGeohash = %s", err.Error())
+		}
+		if res != c.ref {
+			t.Errorf("FromGeohash(%s) = %s, want %s", c.hash, res, c.ref)
+		}
+	}
+
+	if _, err := FromGeohash("dqcja"); !errors.Is(err, geohash.ErrInvalidHash) {
+		t.Errorf("FromGeohash = %v, want %v", err, geohash.ErrInvalidHash)
+	}
+	if _, err := FromGeohash("zz"); !errors.Is(err, ErrPolar) {
+		t.Errorf("FromGeohash = %v, want %v", err, ErrPolar)
+	}
+}
@@ -0,0 +1,80 @@
+package geohash
+
+import "math"
+
+// Direction identifies one of the eight cells adjacent to a geohash cell.
+type Direction int
+
+// Directions are ordered clockwise starting north.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// valid reports whether d is one of the eight defined directions.
+func (d Direction) valid() bool {
+	return d >= North && d <= NorthWest
+}
+
+// directionSteps holds the lat, lng cell offsets for each Direction.
+var directionSteps = [8][2]int{
+	{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+}
+
+// Neighbor returns the cell adjacent to hash in direction d with the same precision.
+// Longitude wraps across the antimeridian.
+// No cell exists beyond the poles; false is returned for a north or south neighbor of a polar cell.
+// An invalid Direction returns hash unchanged and false.
+func Neighbor(hash string, d Direction) (string, bool) {
+	hash = truncateHigh(hash)
+	if !d.valid() {
+		return hash, false
+	}
+	step := directionSteps[d]
+	return neighbor(decodeBox(hash), len(hash), step[0], step[1])
+}
+
+// Neighbors returns the cells adjacent to hash in clockwise order starting north.
+// Neighbors beyond the poles do not exist and are omitted, leaving 5 neighbors for polar cells.
+func Neighbors(hash string) []string {
+	hash = truncateHigh(hash)
+	box := decodeBox(hash)
+
+	neighbors := make([]string, 0, len(directionSteps))
+	for _, step := range directionSteps {
+		if n, ok := neighbor(box, len(hash), step[0], step[1]); ok {
+			neighbors = append(neighbors, n)
+		}
+	}
+	return neighbors
+}
+
+// neighbor returns the cell dLat rows and dLng columns away from box at the specified character precision.
+// The center of the box is moved by whole cell widths and heights and encoded.
+// Using the center avoids the ambiguity of encoding points that fall on a cell edge.
+func neighbor(box Box, precision, dLat, dLng int) (string, bool) {
+	lat, lng := box.Center()
+	lat += float64(dLat) * box.Height()
+	lng += float64(dLng) * box.Width()
+
+	if lat <= -latMax || lat >= latMax {
+		return "", false
+	}
+
+	return encodeBitwiseOr(lat, wrapLng(lng), precision), true
+}
+
+// wrapLng returns lng normalized to [-180, 180).
+func wrapLng(lng float64) float64 {
+	lng = math.Mod(lng+lngMax, 2*lngMax)
+	if lng < 0 {
+		lng += 2 * lngMax
+	}
+	return lng - lngMax
+}
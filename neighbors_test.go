@@ -0,0 +1,51 @@
+package geohash
+
+import (
+	"slices"
+	"testing"
+)
+
+type neighborsCase struct {
+	hash      string
+	neighbors []string
+}
+
+var neighborsCases = []neighborsCase{
+	{"dqcjq", []string{"dqcjw", "dqcjx", "dqcjr", "dqcjp", "dqcjn", "dqcjj", "dqcjm", "dqcjt"}},
+	{"8", []string{"b", "c", "9", "3", "2", "r", "x", "z"}},
+	{"b", []string{"c", "9", "8", "x", "z"}},
+	{"zzzz", []string{"bpbp", "bpbn", "zzzy", "zzzw", "zzzx"}},
+}
+
+func TestNeighbors(t *testing.T) {
+	for _, c := range neighborsCases {
+		res := Neighbors(c.hash)
+
+		if !slices.Equal(res, c.neighbors) {
+			t.Errorf("Neighbors(%s) = %v, want %v", c.hash, res, c.neighbors)
+		}
+	}
+}
+
+func TestNeighbor(t *testing.T) {
+	res, ok := Neighbor("dqcjq", SouthWest)
+	if !ok || res != "dqcjj" {
+		t.Errorf("Neighbor = %s, %t, want dqcjj, true", res, ok)
+	}
+
+	if res, ok := Neighbor("b", North); ok {
+		t.Errorf("Neighbor = %s, %t, want false", res, ok)
+	}
+
+	for _, d := range []Direction{-1, NorthWest + 1} {
+		if res, ok := Neighbor("dqcjq", d); ok || res != "dqcjq" {
+			t.Errorf("Neighbor(%d) = %s, %t, want dqcjq, false", d, res, ok)
+		}
+	}
+}
+
+func BenchmarkNeighbors(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		Neighbors(testHash)
+	}
+}
@@ -0,0 +1,400 @@
+// Package olc implements Open Location Codes (Plus Codes) and conversion between Plus Code areas and geohash cells.
+// Like the geohash package, it is written to document the algorithm as much as to be used.
+// See https://github.com/google/open-location-code/blob/main/docs/specification.md for the specification.
+package olc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bbailey1024/geohash"
+)
+
+const (
+	alphabet     = "23456789CFGHJMPQRVWX"
+	encBase      = int64(len(alphabet))
+	separator    = '+'
+	separatorPos = 8
+	padding      = '0'
+	pairCodeLen  = 10
+	gridCols     = 4
+	gridRows     = 5
+	minCodeLen   = 2
+	maxCodeLen   = 15
+	minTrimLen   = 6
+	latMax       = 90
+	lngMax       = 180
+
+	// Encoding and decoding are performed on integers with these units per degree, avoiding floating point representation errors.
+	// A 10 digit code has a resolution of 1/8000 degrees, and each of the 5 grid digits that follow divide it into 5 rows and 4 columns.
+	pairPrecision     = 8000
+	finalLatPrecision = pairPrecision * gridRows * gridRows * gridRows * gridRows * gridRows
+	finalLngPrecision = pairPrecision * gridCols * gridCols * gridCols * gridCols * gridCols
+
+	// The first pair of digits has a resolution of 20 degrees.
+	firstLatPlace = 20 * finalLatPrecision
+	firstLngPlace = 20 * finalLngPrecision
+)
+
+var (
+	// ErrInvalidCode is returned for codes that are not valid Open Location Codes.
+	ErrInvalidCode = errors.New("olc: invalid code")
+
+	// ErrNotFull is returned for valid codes that are required to be full codes, such as those passed to Decode.
+	ErrNotFull = errors.New("olc: not a full code")
+
+	// ErrNotShort is returned for valid codes that are required to be short codes.
+	ErrNotShort = errors.New("olc: not a short code")
+)
+
+// pairResolutions are the degrees covered by each digit pair of a code.
+var pairResolutions = []float64{20.0, 1.0, .05, .0025, .000125}
+
+// lookup maps an uppercase or lowercase byte to its index in the alphabet, or -1 if it is not in the alphabet.
+var lookup = func() [256]int8 {
+	t := [256]int8{}
+	for i := range t {
+		t[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		t[alphabet[i]] = int8(i)
+		t[alphabet[i]|0x20] = int8(i)
+	}
+	return t
+}()
+
+// CodeArea is the area of a decoded code and the number of digits (excluding the separator and padding) in the code.
+// Like a geohash Box, the min edges are inclusive and the max edges are exclusive.
+type CodeArea struct {
+	geohash.Box
+	Len int
+}
+
+// Center returns the lat, lng coordinates of the center of the area, limited to a latitude of 90 and longitude of 180.
+func (a CodeArea) Center() (float64, float64) {
+	lat, lng := a.Box.Center()
+	return math.Min(lat, latMax), math.Min(lng, lngMax)
+}
+
+// Check returns ErrInvalidCode if code is not a valid full or short code.
+// A valid code has a single separator at an even position no greater than 8.
+// Codes shorter than 8 digits are padded with zeros to the separator, and padded codes may not have digits after the separator.
+// A single digit after the separator is not permitted.
+func Check(code string) error {
+	sep := strings.IndexByte(code, separator)
+	if sep < 0 || sep != strings.LastIndexByte(code, separator) {
+		return fmt.Errorf("%w: %q requires a single separator", ErrInvalidCode, code)
+	}
+	if sep > separatorPos || sep%2 == 1 {
+		return fmt.Errorf("%w: %q has separator at position %d", ErrInvalidCode, code, sep)
+	}
+	if len(code) == 1 {
+		return fmt.Errorf("%w: %q has no digits", ErrInvalidCode, code)
+	}
+	if len(code)-sep-1 == 1 {
+		return fmt.Errorf("%w: %q has a single digit after the separator", ErrInvalidCode, code)
+	}
+
+	pad := strings.IndexByte(code, padding)
+	if pad >= 0 {
+		switch {
+		case pad > sep:
+			return fmt.Errorf("%w: %q has padding after the separator", ErrInvalidCode, code)
+		case sep < separatorPos:
+			return fmt.Errorf("%w: %q is short and padded", ErrInvalidCode, code)
+		case pad == 0 || pad%2 == 1:
+			return fmt.Errorf("%w: %q has padding at position %d", ErrInvalidCode, code, pad)
+		case strings.Trim(code[pad:sep], string(padding)) != "" || sep != len(code)-1:
+			return fmt.Errorf("%w: %q has digits following padding", ErrInvalidCode, code)
+		}
+		code = code[:pad]
+	}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] != separator && lookup[code[i]] < 0 {
+			return fmt.Errorf("%w: %q has invalid character at position %d", ErrInvalidCode, code, i)
+		}
+	}
+	return nil
+}
+
+// CheckFull returns ErrInvalidCode if code is invalid or ErrNotFull if code is a short code.
+// A full code has 8 digits before the separator and the first pair of digits must be within the range of latitude and longitude.
+func CheckFull(code string) error {
+	if err := Check(code); err != nil {
+		return err
+	}
+	if strings.IndexByte(code, separator) < separatorPos {
+		return fmt.Errorf("%w: %q", ErrNotFull, code)
+	}
+	if int64(lookup[code[0]])*20 >= 2*latMax || int64(lookup[code[1]])*20 >= 2*lngMax {
+		return fmt.Errorf("%w: %q is outside the range of latitude or longitude", ErrInvalidCode, code)
+	}
+	return nil
+}
+
+// CheckShort returns ErrInvalidCode if code is invalid or ErrNotShort if code is a full code.
+// A short code has fewer than 8 digits before the separator, omitting leading digits that are recovered from a reference location.
+func CheckShort(code string) error {
+	if err := Check(code); err != nil {
+		return err
+	}
+	if strings.IndexByte(code, separator) == separatorPos {
+		return fmt.Errorf("%w: %q", ErrNotShort, code)
+	}
+	return nil
+}
+
+// Encode returns the Open Location Code of lat, lng with the provided number of digits.
+// Code lengths below 10 must be even, so an odd length is increased by one.
+// Lengths are limited to 2 to 15 digits, and a length of 0 or less uses the standard length of 10.
+// Latitude is clipped to ±90 and longitude is normalized to [-180, 180).
+func Encode(lat, lng float64, codeLen int) string {
+	codeLen = normalizeLen(codeLen)
+	latVal, lngVal := integers(lat, lng)
+
+	// Digits are computed from the least significant, so they are filled from the end.
+	// All 15 digits are computed and truncated to the requested length when the separator is inserted.
+	digits := [maxCodeLen]byte{}
+	for i := maxCodeLen - 1; i >= pairCodeLen; i-- {
+		digits[i] = alphabet[(latVal%gridRows)*gridCols+lngVal%gridCols]
+		latVal /= gridRows
+		lngVal /= gridCols
+	}
+	for i := pairCodeLen - 1; i > 0; i -= 2 {
+		digits[i] = alphabet[lngVal%encBase]
+		digits[i-1] = alphabet[latVal%encBase]
+		latVal /= encBase
+		lngVal /= encBase
+	}
+
+	code := strings.Builder{}
+	code.Grow(maxCodeLen + 1)
+	if codeLen >= separatorPos {
+		code.Write(digits[:separatorPos])
+		code.WriteByte(separator)
+		code.Write(digits[separatorPos:codeLen])
+		return code.String()
+	}
+
+	code.Write(digits[:codeLen])
+	code.WriteString(strings.Repeat(string(padding), separatorPos-codeLen))
+	code.WriteByte(separator)
+	return code.String()
+}
+
+// Decode returns the area of a full code. Digits beyond 15 are ignored.
+// ErrInvalidCode is returned for invalid codes and ErrNotFull for short codes.
+func Decode(code string) (CodeArea, error) {
+	if err := CheckFull(code); err != nil {
+		return CodeArea{}, err
+	}
+
+	digits := strings.ReplaceAll(code, string(separator), "")
+	digits = strings.TrimRight(digits, string(padding))
+	if len(digits) > maxCodeLen {
+		digits = digits[:maxCodeLen]
+	}
+
+	// Each pair of digits divides the cell into 20 rows and 20 columns.
+	// Each grid digit divides the cell into 5 rows and 4 columns, with the digit indexing the rows from the south.
+	// The place value of the last digit is the height and width of the area.
+	var latVal, lngVal int64
+	latPlace, lngPlace := int64(firstLatPlace*encBase), int64(firstLngPlace*encBase)
+	for i := 0; i < len(digits); {
+		if i < pairCodeLen {
+			latPlace /= encBase
+			lngPlace /= encBase
+			latVal += int64(lookup[digits[i]]) * latPlace
+			lngVal += int64(lookup[digits[i+1]]) * lngPlace
+			i += 2
+			continue
+		}
+
+		latPlace /= gridRows
+		lngPlace /= gridCols
+		idx := int64(lookup[digits[i]])
+		latVal += idx / gridCols * latPlace
+		lngVal += idx % gridCols * lngPlace
+		i++
+	}
+
+	return CodeArea{
+		Box: geohash.Box{
+			MinLat: float64(latVal)/finalLatPrecision - latMax,
+			MaxLat: float64(latVal+latPlace)/finalLatPrecision - latMax,
+			MinLng: float64(lngVal)/finalLngPrecision - lngMax,
+			MaxLng: float64(lngVal+lngPlace)/finalLngPrecision - lngMax,
+		},
+		Len: len(digits),
+	}, nil
+}
+
+// Shorten removes leading digits from a full code that can be recovered from a reference location near the code.
+// The closer the reference location, the more digits are removed, with up to 8 digits removed.
+// The code is returned uppercased and unchanged if the reference location is too far away to remove any digits.
+// ErrInvalidCode is returned for invalid codes, padded codes, and codes shorter than 6 digits, and ErrNotFull for short codes.
+func Shorten(code string, lat, lng float64) (string, error) {
+	area, err := Decode(code)
+	if err != nil {
+		return "", err
+	}
+	if strings.IndexByte(code, padding) >= 0 || area.Len < minTrimLen {
+		return "", fmt.Errorf("%w: %q cannot be shortened", ErrInvalidCode, code)
+	}
+	code = strings.ToUpper(code)
+
+	lat, lng = clipLat(lat), normalizeLng(lng)
+
+	// A pair of digits can be removed if the reference location is well within the cell of those digits.
+	// The distance must be less than half the resolution, with 0.3 used instead of 0.5 to provide a margin of safety.
+	centerLat, centerLng := area.Center()
+	distance := math.Max(math.Abs(centerLat-lat), math.Abs(centerLng-lng))
+	for i := len(pairResolutions) - 2; i >= 1; i-- {
+		if distance < pairResolutions[i]*0.3 {
+			return code[(i+1)*2:], nil
+		}
+	}
+	return code, nil
+}
+
+// RecoverNearest returns the full code nearest the reference location that matches a short code.
+// The missing leading digits are taken from the code of the reference location.
+// If the resulting cell is more than half a cell away from the reference location, the adjacent cell is used instead.
+// A full code is returned uppercased. ErrInvalidCode is returned for invalid codes.
+func RecoverNearest(code string, lat, lng float64) (string, error) {
+	if err := CheckFull(code); err == nil {
+		return strings.ToUpper(code), nil
+	}
+	if err := CheckShort(code); err != nil {
+		return "", err
+	}
+	code = strings.ToUpper(code)
+
+	lat, lng = clipLat(lat), normalizeLng(lng)
+
+	padLen := separatorPos - strings.IndexByte(code, separator)
+	resolution := math.Pow(float64(encBase), float64(2-padLen/2))
+	halfRes := resolution / 2
+
+	area, err := Decode(Encode(lat, lng, 0)[:padLen] + code)
+	if err != nil {
+		return "", err
+	}
+
+	centerLat, centerLng := area.Center()
+	if lat+halfRes < centerLat && centerLat-resolution >= -latMax {
+		centerLat -= resolution
+	} else if lat-halfRes > centerLat && centerLat+resolution <= latMax {
+		centerLat += resolution
+	}
+	if lng+halfRes < centerLng {
+		centerLng -= resolution
+	} else if lng-halfRes > centerLng {
+		centerLng += resolution
+	}
+
+	return Encode(centerLat, centerLng, area.Len), nil
+}
+
+// Geohashes returns the geohash strings of the provided character precision covering the area of a full code.
+// Acceptable precision values are 1 to 12 characters.
+// ErrInvalidCode is returned for invalid codes and ErrNotFull for short codes.
+func Geohashes(code string, precision int) ([]string, error) {
+	area, err := Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	return geohash.CoverBox(area.Box, precision), nil
+}
+
+// FromGeohash returns the full codes of codeLen digits covering the cell of a geohash string of 1 to 12 characters.
+// Codes are ordered row by row starting in the southwest corner.
+// The code length is normalized as it is by Encode. geohash.ErrInvalidHash is returned for invalid geohash strings.
+func FromGeohash(hash string, codeLen int) ([]string, error) {
+	if _, err := geohash.ParseHash(hash); err != nil {
+		return nil, err
+	}
+	box := geohash.DecodeBox(hash)
+	codeLen = normalizeLen(codeLen)
+
+	// The height and width of a code of codeLen digits in final precision units.
+	latStep, lngStep := int64(firstLatPlace), int64(firstLngPlace)
+	for i := 2; i < min(codeLen, pairCodeLen); i += 2 {
+		latStep /= encBase
+		lngStep /= encBase
+	}
+	for i := pairCodeLen; i < codeLen; i++ {
+		latStep /= gridRows
+		lngStep /= gridCols
+	}
+
+	// The rows and columns overlapping the box. The max edges are exclusive, so they are rounded up.
+	lat0 := int64(math.Floor((box.MinLat + latMax) * finalLatPrecision / float64(latStep)))
+	lat1 := int64(math.Ceil((box.MaxLat + latMax) * finalLatPrecision / float64(latStep)))
+	lng0 := int64(math.Floor((box.MinLng + lngMax) * finalLngPrecision / float64(lngStep)))
+	lng1 := int64(math.Ceil((box.MaxLng + lngMax) * finalLngPrecision / float64(lngStep)))
+
+	codes := []string{}
+	for lat := lat0; lat < lat1; lat++ {
+		for lng := lng0; lng < lng1; lng++ {
+			centerLat := (float64(lat*latStep)+float64(latStep)/2)/finalLatPrecision - latMax
+			centerLng := (float64(lng*lngStep)+float64(lngStep)/2)/finalLngPrecision - lngMax
+			codes = append(codes, Encode(centerLat, centerLng, codeLen))
+		}
+	}
+	return codes, nil
+}
+
+// normalizeLen returns a valid code length as described by Encode.
+func normalizeLen(codeLen int) int {
+	switch {
+	case codeLen <= 0:
+		return pairCodeLen
+	case codeLen < minCodeLen:
+		return minCodeLen
+	case codeLen < pairCodeLen && codeLen%2 == 1:
+		return codeLen + 1
+	case codeLen > maxCodeLen:
+		return maxCodeLen
+	default:
+		return codeLen
+	}
+}
+
+// integers returns the lat, lng coordinates as integers of the final precision, offset to be positive.
+// Coordinates are floored rather than rounded, as the reference implementation does, so a point never rounds into the next cell.
+// Latitude is clipped, with 90 moved into the northernmost cell as no cell has 90 as its min edge.
+// Longitude is normalized to [-180, 180).
+func integers(lat, lng float64) (int64, int64) {
+	latVal := int64(math.Floor(lat*finalLatPrecision)) + latMax*finalLatPrecision
+	if latVal < 0 {
+		latVal = 0
+	} else if latVal >= 2*latMax*finalLatPrecision {
+		latVal = 2*latMax*finalLatPrecision - 1
+	}
+
+	lngVal := int64(math.Floor(lng*finalLngPrecision)) + lngMax*finalLngPrecision
+	lngVal %= 2 * lngMax * finalLngPrecision
+	if lngVal < 0 {
+		lngVal += 2 * lngMax * finalLngPrecision
+	}
+
+	return latVal, lngVal
+}
+
+// clipLat returns lat limited to ±90.
+func clipLat(lat float64) float64 {
+	return math.Max(-latMax, math.Min(latMax, lat))
+}
+
+// normalizeLng returns lng normalized to [-180, 180).
+func normalizeLng(lng float64) float64 {
+	lng = math.Mod(lng+lngMax, 2*lngMax)
+	if lng < 0 {
+		lng += 2 * lngMax
+	}
+	return lng - lngMax
+}
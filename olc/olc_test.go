@@ -0,0 +1,219 @@
+package olc
+
+import (
+	"encoding/csv"
+	"errors"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bbailey1024/geohash"
+)
+
+// readCSV returns the records of a test data file, skipping comment lines beginning with #.
+func readCSV(t *testing.T, name string) [][]string {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Open = %s", err.Error())
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll = %s", err.Error())
+	}
+	return records
+}
+
+// parseFloats parses every field of s as a float64.
+func parseFloats(t *testing.T, s ...string) []float64 {
+	t.Helper()
+
+	f := make([]float64, len(s))
+	for i := range s {
+		v, err := strconv.ParseFloat(s[i], 64)
+		if err != nil {
+			t.Fatalf("ParseFloat = %s", err.Error())
+		}
+		f[i] = v
+	}
+	return f
+}
+
+func TestEncode(t *testing.T) {
+	for _, r := range readCSV(t, "encoding.csv") {
+		f := parseFloats(t, r[0], r[1])
+		length, _ := strconv.Atoi(r[2])
+
+		if res := Encode(f[0], f[1], length); res != r[3] {
+			t.Errorf("Encode(%f, %f, %d) = %s, want %s", f[0], f[1], length, res, r[3])
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, r := range readCSV(t, "decoding.csv") {
+		length, _ := strconv.Atoi(r[1])
+		f := parseFloats(t, r[2:]...)
+
+		res, err := Decode(r[0])
+		if err != nil {
+			t.Fatalf("Decode(%s) = %s", r[0], err.Error())
+		}
+
+		got := []float64{res.MinLat, res.MinLng, res.MaxLat, res.MaxLng}
+		for i := range got {
+			if math.Abs(got[i]-f[i]) > 1e-10 {
+				t.Errorf("Decode(%s) = %v, want %v", r[0], got, f)
+				break
+			}
+		}
+		if res.Len != length {
+			t.Errorf("Decode(%s) = %d digits, want %d", r[0], res.Len, length)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, r := range readCSV(t, "validityTests.csv") {
+		valid, short, full := r[1] == "true", r[2] == "true", r[3] == "true"
+
+		if res := Check(r[0]) == nil; res != valid {
+			t.Errorf("Check(%s) = %t, want %t", r[0], res, valid)
+		}
+		if res := CheckShort(r[0]) == nil; res != short {
+			t.Errorf("CheckShort(%s) = %t, want %t", r[0], res, short)
+		}
+		if res := CheckFull(r[0]) == nil; res != full {
+			t.Errorf("CheckFull(%s) = %t, want %t", r[0], res, full)
+		}
+	}
+
+	if err := CheckFull("2345+G6"); !errors.Is(err, ErrNotFull) {
+		t.Errorf("CheckFull = %v, want %v", err, ErrNotFull)
+	}
+	if err := CheckShort("8FWC2345+G6"); !errors.Is(err, ErrNotShort) {
+		t.Errorf("CheckShort = %v, want %v", err, ErrNotShort)
+	}
+	if err := CheckFull("X2222222+22"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("CheckFull = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestShortCodes(t *testing.T) {
+	for _, r := range readCSV(t, "shortCodeTests.csv") {
+		full, short, kind := r[0], r[3], r[4]
+		f := parseFloats(t, r[1], r[2])
+
+		if kind == "B" || kind == "S" {
+			res, err := Shorten(full, f[0], f[1])
+			if err != nil || res != short {
+				t.Errorf("Shorten(%s, %f, %f) = %s, %v, want %s", full, f[0], f[1], res, err, short)
+			}
+		}
+
+		if kind == "B" || kind == "R" {
+			res, err := RecoverNearest(short, f[0], f[1])
+			if err != nil || res != full {
+				t.Errorf("RecoverNearest(%s, %f, %f) = %s, %v, want %s", short, f[0], f[1], res, err, full)
+			}
+		}
+	}
+
+	if _, err := Shorten("8FWC0000+", 0, 0); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Shorten = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestGeohashes(t *testing.T) {
+	// The 4 digit code 7FG40000+ is the 1 degree cell from 20, 2 to 21, 3.
+	res, err := Geohashes("7FG40000+", 3)
+	if err != nil {
+		t.Fatalf("Geohashes = %s", err.Error())
+	}
+
+	box := geohash.Box{MinLat: 20, MaxLat: 21, MinLng: 2, MaxLng: 3}
+	if len(res) == 0 {
+		t.Fatalf("Geohashes = no cells")
+	}
+	for _, hash := range res {
+		b := geohash.DecodeBox(hash)
+		if b.MaxLat <= box.MinLat || b.MinLat >= box.MaxLat || b.MaxLng <= box.MinLng || b.MinLng >= box.MaxLng {
+			t.Errorf("Geohashes = %s (%+v), does not overlap %+v", hash, b, box)
+		}
+	}
+	if !slices.Contains(res, geohash.EncodePrecision(20.5, 2.5, 3)) {
+		t.Errorf("Geohashes = %v, want to contain cell of the center", res)
+	}
+
+	if _, err := Geohashes("2345+G6", 3); !errors.Is(err, ErrNotFull) {
+		t.Errorf("Geohashes = %v, want %v", err, ErrNotFull)
+	}
+}
+
+func TestFromGeohash(t *testing.T) {
+	// Geohash "s0" spans 0 to 5.625 latitude and 0 to 11.25 longitude, overlapping 6 rows and 12 columns of 4 digit codes.
+	res, err := FromGeohash("s0", 4)
+	if err != nil {
+		t.Fatalf("FromGeohash = %s", err.Error())
+	}
+	if len(res) != 6*12 || res[0] != "6FG20000+" {
+		t.Errorf("FromGeohash = %d codes starting %s, want 72 starting 6FG20000+", len(res), res[0])
+	}
+
+	box := geohash.DecodeBox("s0")
+	for _, code := range res {
+		area, err := Decode(code)
+		if err != nil {
+			t.Fatalf("Decode = %s", err.Error())
+		}
+		lat, lng := area.Center()
+		if math.Floor(lat) > box.MaxLat || math.Floor(lng) > box.MaxLng {
+			t.Errorf("FromGeohash = %s, outside %+v", code, box)
+		}
+	}
+
+	if _, err := FromGeohash("s0a", 4); !errors.Is(err, geohash.ErrInvalidHash) {
+		t.Errorf("FromGeohash = %v, want %v", err, geohash.ErrInvalidHash)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, length := range []int{2, 4, 6, 8, 10, 11, 12, 13, 14, 15} {
+		code := Encode(testLat, testLng, length)
+		area, err := Decode(code)
+		if err != nil {
+			t.Fatalf("Decode(%s) = %s", code, err.Error())
+		}
+		if !area.Contains(testLat, testLng) || area.Len != length {
+			t.Errorf("Decode(%s) = %+v, want %d digits containing %f, %f", code, area, length, testLat, testLng)
+		}
+		if strings.Count(code, "+") != 1 {
+			t.Errorf("Encode = %s, want single separator", code)
+		}
+	}
+}
+
+const (
+	testLat = 38.05339909138269
+	testLng = -84.70121386485815
+)
+
+func BenchmarkEncode(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		Encode(testLat, testLng, 10)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		Decode("86CQ2735+9H")
+	}
+}
@@ -0,0 +1,216 @@
+package geohash
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// polylineDepth limits the bisection of a step between two cells that are not adjacent.
+// A step that remains diagonal after this many bisections passes exactly through a cell corner.
+const polylineDepth = 40
+
+// polylineMaxColumns limits the columns searched either side of a route cell when buffering.
+// Columns narrow to nothing at the poles, where the buffer of a cell touching a pole would otherwise search its whole row.
+// Rows of up to precision 6 are searched in full; finer polar rows are truncated.
+const polylineMaxColumns = 1 << 14
+
+// CoverPolyline returns the geohash strings of the cells of the provided character precision that a route passes through,
+// in the order the route first enters them. Acceptable precision values are 1 to 12 characters.
+// Each segment between consecutive points follows the great circle, sampled at intervals of half a cell height.
+// Consecutive samples in cells that do not share an edge are bisected until they do, so the cells form a connected path.
+// A route passing exactly through a cell corner includes the cell north or south of the corner to stay connected.
+//
+// A bufferMeters greater than 0 widens the route into a corridor. Cells within bufferMeters of a segment are appended
+// after the route cells, segment by segment, and within a segment row by row from the south. A cell is included when its
+// center is within bufferMeters plus half its diagonal of a segment, so the corridor errs toward including cells.
+// Near the poles, where a corridor may wrap entire rows of cells, at most 16384 columns either side of each route cell
+// are searched, so corridors at precision 7 or more are truncated to a wedge of each polar row.
+func CoverPolyline(points []Point, precision int, bufferMeters float64) []string {
+	precision = validate(precisionMin, precisionMax, precision)
+	if len(points) == 0 {
+		return []string{}
+	}
+
+	c := polylineCover{bits: precision * 5, seen: map[uint64]bool{}}
+	if len(points) == 1 {
+		c.visit(encodeIntClamp(points[0].Lat, points[0].Lng, c.bits), 0)
+	}
+	for i := 0; i+1 < len(points); i++ {
+		c.segment(points[i], points[i+1], i)
+	}
+
+	if bufferMeters > 0 {
+		c.buffer(points, bufferMeters)
+	}
+
+	cells := make([]string, len(c.cells))
+	for i, hash := range c.cells {
+		cells[i] = EncodeIntToStr(hash, precision)
+	}
+	return cells
+}
+
+// polylineCover holds the cells of a route as they are found.
+// segments holds the index of the segment each route cell was found on, used when buffering.
+type polylineCover struct {
+	bits     int
+	cells    []uint64
+	segments []int
+	seen     map[uint64]bool
+}
+
+// segment adds the cells along the great circle from a to b, sampling at half a cell height and bisecting gaps.
+func (c *polylineCover) segment(a, b Point, seg int) {
+	latBits, _ := gridBits(c.bits)
+	step := 2 * latMax / math.Exp2(float64(latBits)) * metersPerDegree / 2
+	n := max(1, int(math.Ceil(haversine(a, b)/step)))
+
+	t0, h0 := 0.0, encodeIntClamp(a.Lat, a.Lng, c.bits)
+	if len(c.cells) == 0 {
+		c.visit(h0, seg)
+	}
+	for i := 1; i <= n; i++ {
+		t1 := float64(i) / float64(n)
+		p := interpolate(a, b, t1)
+		h1 := encodeIntClamp(p.Lat, p.Lng, c.bits)
+		c.connect(a, b, t0, t1, h0, h1, seg, 0)
+		t0, h0 = t1, h1
+	}
+}
+
+// connect adds the cells between the samples at t0 and t1 of the segment from a to b, then the cell h1.
+// When h0 and h1 do not share an edge, the step is bisected and each half is connected.
+func (c *polylineCover) connect(a, b Point, t0, t1 float64, h0, h1 uint64, seg, depth int) {
+	dLat, dLng := gridOffset(h0, h1, c.bits)
+	dLat, dLng = abs(dLat), abs(dLng)
+	if dLat+dLng <= 1 {
+		c.visit(h1, seg)
+		return
+	}
+
+	if depth == polylineDepth {
+		// The step passes through a corner; stay connected through the cell sharing the row of h1 and column of h0.
+		if dLat == 1 && dLng == 1 {
+			lat1, _ := gridCell(h1, c.bits)
+			_, lng0 := gridCell(h0, c.bits)
+			c.visit(gridHash(lat1, lng0, c.bits), seg)
+		}
+		c.visit(h1, seg)
+		return
+	}
+
+	tm := (t0 + t1) / 2
+	p := interpolate(a, b, tm)
+	hm := encodeIntClamp(p.Lat, p.Lng, c.bits)
+	c.connect(a, b, t0, tm, h0, hm, seg, depth+1)
+	c.connect(a, b, tm, t1, hm, h1, seg, depth+1)
+}
+
+// visit adds h to the cells of the route if it has not been seen.
+func (c *polylineCover) visit(h uint64, seg int) {
+	if c.seen[h] {
+		return
+	}
+	c.seen[h] = true
+	c.cells = append(c.cells, h)
+	c.segments = append(c.segments, seg)
+}
+
+// polylineSpan is an interval of columns of a row searched when buffering.
+// Columns are not wrapped, so first may be negative and last may exceed the columns of the row.
+type polylineSpan struct {
+	row, first, last int64
+}
+
+// buffer appends the cells within r meters of the route, searching the rows and columns around each route cell.
+// The route cells of a segment are consecutive, so their searches are merged into one set of column intervals per row,
+// and each cell near a segment is tested once rather than once for every route cell it is near.
+func (c *polylineCover) buffer(points []Point, r float64) {
+	latBits, lngBits := gridBits(c.bits)
+	rows, cols := int64(1)<<latBits, int64(1)<<lngBits
+
+	// Rows have the same height everywhere, so the rows to search are the same for every route cell.
+	// Both the rows and columns searched are capped by the size of the grid before converting to integers.
+	height := 2 * latMax / float64(rows) * metersPerDegree
+	kLat := int64(math.Min(math.Ceil(r/height), float64(rows)))
+
+	route := len(c.cells)
+	for i, j := 0, 0; i < route; i = j {
+		seg := c.segments[i]
+		a, b := points[seg], points[seg]
+		if seg+1 < len(points) {
+			b = points[seg+1]
+		}
+
+		spans := []polylineSpan{}
+		for j = i; j < route && c.segments[j] == seg; j++ {
+			lat, lng := gridCell(c.cells[j], c.bits)
+			for row := max(0, int64(lat)-kLat); row <= min(rows-1, int64(lat)+kLat); row++ {
+				kLng, _ := c.rowSpan(row, r)
+				spans = append(spans, polylineSpan{row: row, first: int64(lng) - kLng, last: int64(lng) + kLng})
+			}
+		}
+
+		for _, span := range mergeSpans(spans) {
+			_, diagonal := c.rowSpan(span.row, r)
+			for col := span.first; col <= min(span.last, span.first+cols-1); col++ {
+				h := gridHash(uint32(span.row), uint32((col%cols+cols)%cols), c.bits)
+				if c.seen[h] {
+					continue
+				}
+
+				centerLat, centerLng := WGS84.DecodeIntBox(h, c.bits).Center()
+				if segmentDistance(Point{centerLat, centerLng}, a, b) <= r+diagonal {
+					c.seen[h] = true
+					c.cells = append(c.cells, h)
+				}
+			}
+		}
+	}
+}
+
+// rowSpan returns the columns to search either side of a cell in row to find the cells within r meters of it,
+// and half the diagonal of the cells of the row in meters.
+// Columns are narrowest at the poleward edge of the row, used to find the columns to search,
+// and widest at the equatorward edge, used for the cell diagonal.
+func (c *polylineCover) rowSpan(row int64, r float64) (int64, float64) {
+	latBits, lngBits := gridBits(c.bits)
+	rows, cols := int64(1)<<latBits, int64(1)<<lngBits
+	height := 2 * latMax / float64(rows)
+	minLat := -latMax + float64(row)*height
+	maxLat := minLat + height
+
+	poleward := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	equatorward := math.Min(math.Abs(minLat), math.Abs(maxLat))
+	if minLat < 0 && maxLat > 0 {
+		equatorward = 0
+	}
+	equator := 2 * lngMax / float64(cols) * metersPerDegree
+	width := equator * math.Cos(poleward*math.Pi/180)
+	diagonal := math.Hypot(height*metersPerDegree, equator*math.Cos(equatorward*math.Pi/180)) / 2
+
+	kLng := float64(min(cols/2, polylineMaxColumns))
+	if width > 0 {
+		kLng = math.Min(kLng, math.Ceil(r/width))
+	}
+	return int64(kLng), diagonal
+}
+
+// mergeSpans sorts spans by row and first column and merges those of the same row that overlap or are contiguous,
+// reusing the backing array of spans.
+func mergeSpans(spans []polylineSpan) []polylineSpan {
+	slices.SortFunc(spans, func(a, b polylineSpan) int {
+		return cmp.Or(cmp.Compare(a.row, b.row), cmp.Compare(a.first, b.first))
+	})
+
+	merged := spans[:0]
+	for _, s := range spans {
+		if n := len(merged); n > 0 && merged[n-1].row == s.row && merged[n-1].last+1 >= s.first {
+			merged[n-1].last = max(merged[n-1].last, s.last)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
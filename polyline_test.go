@@ -0,0 +1,156 @@
+package geohash
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// connected reports whether every consecutive pair of cells shares an edge.
+func connected(t *testing.T, cells []string) {
+	t.Helper()
+
+	bits := len(cells[0]) * 5
+	for i := 1; i < len(cells); i++ {
+		dLat, dLng := gridOffset(EncodeStrToInt(cells[i-1]), EncodeStrToInt(cells[i]), bits)
+		if abs(dLat)+abs(dLng) != 1 {
+			t.Errorf("CoverPolyline cells %s and %s do not share an edge", cells[i-1], cells[i])
+		}
+	}
+}
+
+func TestCoverPolyline(t *testing.T) {
+	testCases := []struct {
+		points    []Point
+		precision int
+	}{
+		// A route through Manhattan with a diagonal segment.
+		{[]Point{{40.7033, -74.0170}, {40.7580, -73.9855}, {40.7812, -73.9665}}, 7},
+		// A long great-circle segment from New York to London.
+		{[]Point{{40.7128, -74.0060}, {51.5074, -0.1278}}, 4},
+		// A segment crossing the antimeridian.
+		{[]Point{{-17.7, 178.5}, {-13.8, -171.8}}, 4},
+	}
+
+	for _, c := range testCases {
+		cells := CoverPolyline(c.points, c.precision, 0)
+		connected(t, cells)
+
+		first := c.points[0]
+		if want := EncodePrecision(first.Lat, first.Lng, c.precision); cells[0] != want {
+			t.Errorf("CoverPolyline = %s first, want %s", cells[0], want)
+		}
+		for _, p := range c.points {
+			if hash := EncodePrecision(p.Lat, p.Lng, c.precision); !slices.Contains(cells, hash) {
+				t.Errorf("CoverPolyline does not contain %s", hash)
+			}
+		}
+
+		// Every sampled point along the route is within a returned cell.
+		for i := 0; i+1 < len(c.points); i++ {
+			for f := 0.0; f <= 1; f += 0.001 {
+				p := interpolate(c.points[i], c.points[i+1], f)
+				if hash := EncodePrecision(p.Lat, p.Lng, c.precision); !slices.Contains(cells, hash) {
+					t.Fatalf("CoverPolyline does not contain %s at %f, %f", hash, p.Lat, p.Lng)
+				}
+			}
+		}
+	}
+
+	if res := CoverPolyline(nil, 5, 100); len(res) != 0 {
+		t.Errorf("CoverPolyline = %v, want empty", res)
+	}
+	if res := CoverPolyline([]Point{{40.7, -74}}, 5, 0); !slices.Equal(res, []string{EncodePrecision(40.7, -74, 5)}) {
+		t.Errorf("CoverPolyline = %v, want the cell of the point", res)
+	}
+}
+
+func TestCoverPolylineBuffer(t *testing.T) {
+	points := []Point{{40.7033, -74.0170}, {40.7580, -73.9855}}
+	route := CoverPolyline(points, 7, 0)
+	corridor := CoverPolyline(points, 7, 500)
+
+	if !slices.Equal(corridor[:len(route)], route) {
+		t.Errorf("CoverPolyline corridor does not start with the route cells")
+	}
+
+	// Points 400m either side of the route are covered and points 2km away are not.
+	mid := interpolate(points[0], points[1], 0.5)
+	for _, d := range []float64{-400, 400} {
+		lat := mid.Lat + d/metersPerDegree*math.Cos(bearing(points[0], points[1]))
+		lng := mid.Lng - d/metersPerDegree/math.Cos(mid.Lat*math.Pi/180)*math.Sin(bearing(points[0], points[1]))
+		if hash := EncodePrecision(lat, lng, 7); !slices.Contains(corridor, hash) {
+			t.Errorf("CoverPolyline corridor does not contain %s, %.0fm from the route", hash, d)
+		}
+
+		lat = mid.Lat + 5*d/metersPerDegree*math.Cos(bearing(points[0], points[1]))
+		lng = mid.Lng - 5*d/metersPerDegree/math.Cos(mid.Lat*math.Pi/180)*math.Sin(bearing(points[0], points[1]))
+		if hash := EncodePrecision(lat, lng, 7); slices.Contains(corridor, hash) {
+			t.Errorf("CoverPolyline corridor contains %s, %.0fm from the route", hash, 5*d)
+		}
+	}
+}
+
+func TestCoverPolylineBufferPolar(t *testing.T) {
+	// The polar row of a coarse precision is searched in full, so the corridor wraps across the pole.
+	points := []Point{{89.5, 0}, {89.9, 0}}
+	corridor := CoverPolyline(points, 4, 50000)
+	for _, lng := range []float64{90, 180, -90} {
+		if hash := EncodePrecision(89.9, lng, 4); !slices.Contains(corridor, hash) {
+			t.Errorf("CoverPolyline corridor does not contain %s across the pole", hash)
+		}
+	}
+
+	// A fine precision route reaching the pole completes, with the search of its polar rows truncated.
+	points = []Point{{89.9999999, 10}, {90, 10}}
+	route := CoverPolyline(points, 12, 0)
+	corridor = CoverPolyline(points, 12, 0.05)
+	connected(t, route)
+	if !slices.Equal(corridor[:len(route)], route) || len(corridor) <= len(route) {
+		t.Errorf("CoverPolyline corridor = %d cells, want more than the %d route cells", len(corridor), len(route))
+	}
+	if want := EncodePrecision(89.99999999, 10, 12); route[len(route)-1] != want {
+		t.Errorf("CoverPolyline = %s last, want %s", route[len(route)-1], want)
+	}
+}
+
+func TestCoverPolylineBufferWorld(t *testing.T) {
+	// A buffer wider than the world covers every cell exactly once, with the rows and columns searched capped by the grid.
+	for _, r := range []float64{1e8, math.Inf(1)} {
+		res := CoverPolyline([]Point{{40.7, -74}, {51.5, 0}}, 2, r)
+		if len(res) != 1024 {
+			t.Errorf("CoverPolyline(%g) = %d cells, want 1024", r, len(res))
+		}
+		slices.Sort(res)
+		if len(slices.Compact(res)) != 1024 {
+			t.Errorf("CoverPolyline(%g) contains duplicates", r)
+		}
+	}
+}
+
+func TestSegmentDistance(t *testing.T) {
+	a, b := Point{0, 0}, Point{0, 10}
+
+	testCases := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{1, 5}, metersPerDegree},
+		{Point{0, -1}, metersPerDegree},
+		{Point{0, 11}, metersPerDegree},
+		{Point{0, 5}, 0},
+	}
+
+	for _, c := range testCases {
+		if res := segmentDistance(c.p, a, b); math.Abs(res-c.want) > 1 {
+			t.Errorf("segmentDistance(%+v) = %f, want %f", c.p, res, c.want)
+		}
+	}
+}
+
+func BenchmarkCoverPolyline(b *testing.B) {
+	points := []Point{{40.7033, -74.0170}, {40.7580, -73.9855}, {40.7812, -73.9665}}
+	for n := 0; n < b.N; n++ {
+		CoverPolyline(points, 7, 200)
+	}
+}
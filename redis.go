@@ -0,0 +1,73 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// RedisBits is the bit precision of the geohash integers Redis stores as sorted set scores (26 bits per axis).
+	RedisBits = 52
+
+	// RedisLatMax is the latitude limit of Redis geo commands, the limit of the Web Mercator projection (EPSG:3857).
+	RedisLatMax = 85.05112878
+
+	redisStep = RedisBits / 2
+)
+
+// ErrRedisRange is returned by EncodeRedis for coordinates that Redis rejects.
+var ErrRedisRange = errors.New("geohash: coordinates outside redis geo limits")
+
+// EncodeRedis returns the 52-bit geohash integer Redis stores as the score of a point added with GEOADD.
+// Redis bounds latitude to ±85.05112878 rather than ±90, so the score is not a standard geohash integer.
+// ErrRedisRange is returned for a latitude beyond ±85.05112878 or a longitude beyond ±180, matching GEOADD.
+// Points on the max edge (latitude 85.05112878 or longitude 180) are encoded in the last cell of the axis,
+// whereas Redis overflows the 26 bits of the axis for these coordinates.
+func EncodeRedis(lat, lng float64) (uint64, error) {
+	if lat < -RedisLatMax || lat > RedisLatMax || lng < -lngMax || lng > lngMax {
+		return 0, fmt.Errorf("%w: %f, %f", ErrRedisRange, lat, lng)
+	}
+
+	// Redis computes the offset as ((x - min) / (max - min)) * 2^26 and truncates.
+	// encodeRange computes the same division scaled by 2^32 rather than 2^26.
+	// As scaling by a power of two is exact, discarding the extra 6 bits of each axis produces identical values.
+	lat32 := clampRange(lat, -RedisLatMax, RedisLatMax)
+	lng32 := clampRange(lng, -lngMax, lngMax)
+
+	return interleave(lat32, lng32) >> (64 - RedisBits), nil
+}
+
+// DecodeRedis returns the lat, lng coordinates Redis returns from GEOPOS for a 52-bit score.
+// The center of the cell is computed using the same floating point operations as Redis so the results are identical.
+func DecodeRedis(score uint64) (float64, float64) {
+	box := DecodeRedisBox(score)
+	lat, lng := box.Center()
+	lat = math.Max(-RedisLatMax, math.Min(RedisLatMax, lat))
+	lng = math.Max(-lngMax, math.Min(lngMax, lng))
+	return lat, lng
+}
+
+// DecodeRedisBox returns the bounding box of the cell described by a 52-bit Redis score.
+func DecodeRedisBox(score uint64) Box {
+	lat32, lng32 := deinterleave(score)
+	cells := float64(uint64(1) << redisStep)
+	latScale := 2 * RedisLatMax
+	lngScale := 2 * lngMax
+
+	return Box{
+		MinLat: -RedisLatMax + (float64(lat32)/cells)*latScale,
+		MaxLat: -RedisLatMax + (float64(lat32+1)/cells)*latScale,
+		MinLng: -lngMax + (float64(lng32)/cells)*lngScale,
+		MaxLng: -lngMax + (float64(lng32+1)/cells)*lngScale,
+	}
+}
+
+// EncodeRedisToStr returns the standard 11 character geohash string Redis returns from GEOHASH for a 52-bit score.
+// The score is decoded using the Redis latitude limits and re-encoded using the standard limits of ±90.
+// A standard 11 character geohash requires 55 bits, but only 52 are available, so the final character is always "0".
+func EncodeRedisToStr(score uint64) string {
+	lat, lng := DecodeRedis(score)
+	hash := encodeInt(lat, lng, RedisBits)
+	return EncodeIntToStr(hash>>2, 10) + "0"
+}
@@ -0,0 +1,73 @@
+package geohash
+
+import (
+	"errors"
+	"testing"
+)
+
+// Redis test vectors from the GEOADD, GEOPOS and GEOHASH command documentation.
+type redisCase struct {
+	lat, lng       float64
+	score          uint64
+	posLat, posLng float64
+	hash           string
+}
+
+var redisCases = []redisCase{
+	{38.115556, 13.361389, 3479099956230698, 38.11555639549629859, 13.36138933897018433, "sqc8b49rny0"},
+	{37.502669, 15.087269, 3479447370796909, 37.50266842333162032, 15.08726745843887329, "sqdtr74hyu0"},
+}
+
+func TestEncodeRedis(t *testing.T) {
+	for _, c := range redisCases {
+		res, err := EncodeRedis(c.lat, c.lng)
+		if err != nil {
+			t.Fatalf("EncodeRedis = %s", err.Error())
+		}
+
+		if res != c.score {
+			t.Errorf("EncodeRedis = %d, want %d", res, c.score)
+		}
+	}
+
+	for _, p := range [][2]float64{{85.06, 0}, {-85.06, 0}, {0, 180.1}, {0, -180.1}} {
+		if _, err := EncodeRedis(p[0], p[1]); !errors.Is(err, ErrRedisRange) {
+			t.Errorf("EncodeRedis(%f, %f) = %v, want %v", p[0], p[1], err, ErrRedisRange)
+		}
+	}
+
+	if res, _ := EncodeRedis(RedisLatMax, lngMax); res != 1<<RedisBits-1 {
+		t.Errorf("EncodeRedis = %x, want %x", res, uint64(1<<RedisBits-1))
+	}
+}
+
+func TestDecodeRedis(t *testing.T) {
+	for _, c := range redisCases {
+		lat, lng := DecodeRedis(c.score)
+
+		// Redis formats GEOPOS with 17 significant digits, which round trip float64 exactly.
+		if lat != c.posLat || lng != c.posLng {
+			t.Errorf("DecodeRedis = %.17f, %.17f, want %.17f, %.17f", lat, lng, c.posLat, c.posLng)
+		}
+
+		if box := DecodeRedisBox(c.score); !box.Contains(c.lat, c.lng) {
+			t.Errorf("DecodeRedisBox = %+v, does not contain %f, %f", box, c.lat, c.lng)
+		}
+	}
+}
+
+func TestEncodeRedisToStr(t *testing.T) {
+	for _, c := range redisCases {
+		res := EncodeRedisToStr(c.score)
+
+		if res != c.hash {
+			t.Errorf("EncodeRedisToStr = %s, want %s", res, c.hash)
+		}
+	}
+}
+
+func BenchmarkEncodeRedis(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		EncodeRedis(testLat, testLng)
+	}
+}
@@ -0,0 +1,225 @@
+package geohash
+
+import (
+	"math"
+	"slices"
+)
+
+// Set is a set of geohash cells of mixed precision up to 12 characters.
+// Cells are stored as sorted, disjoint ranges of 64-bit geohash integers, where a cell covers every integer sharing its bits.
+// A cell and its children, or a complete group of 32 siblings and their parent, cover the same integers,
+// so merging the ranges removes children covered by ancestors and replaces full sibling groups with their parent.
+// A region is stored as a handful of ranges regardless of the number of cells along its edges,
+// which uses far less memory than a map of fixed precision strings.
+// The zero value is an empty set. A Set is not safe for concurrent use, as reads normalize cells added since the last read.
+type Set struct {
+	ranges []IntRange
+	dirty  bool
+}
+
+// NewSet returns a Set of geohash strings. ErrInvalidHash is returned for invalid geohash strings.
+func NewSet(hashes ...string) (*Set, error) {
+	s := &Set{}
+	for _, h := range hashes {
+		if err := s.Add(h); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds the cell of a geohash string of 0 to 12 characters, where the empty string is the whole world.
+// Cells are appended and normalized on the next read, so adding many cells is not quadratic.
+// ErrInvalidHash is returned for invalid geohash strings.
+func (s *Set) Add(hash string) error {
+	h, err := ParseHash(hash)
+	if err != nil {
+		return err
+	}
+	s.ranges = append(s.ranges, hashRange(h))
+	s.dirty = true
+	return nil
+}
+
+// Normalize sorts and merges the cells of the set.
+// Children covered by ancestors are removed and full sibling groups are merged into their parent.
+// Every other method normalizes as needed, so calling Normalize is only required to control when the work is done.
+func (s *Set) Normalize() {
+	if s.dirty {
+		s.ranges = mergeRanges(s.ranges)
+		s.dirty = false
+	}
+}
+
+// Contains reports whether the cell of a geohash string is entirely within the set.
+// Invalid geohash strings are not contained.
+func (s *Set) Contains(hash string) bool {
+	h, err := ParseHash(hash)
+	if err != nil {
+		return false
+	}
+	r := hashRange(h)
+	i, ok := s.find(r.Min)
+	return ok && s.ranges[i].Max >= r.Max
+}
+
+// ContainsPoint reports whether the lat, lng coordinates fall within a cell of the set.
+// Points on the max edges of 90 and 180 fall within the northernmost and easternmost cells.
+func (s *Set) ContainsPoint(lat, lng float64) bool {
+	_, ok := s.find(encodeIntClamp(lat, lng, bitsMax))
+	return ok
+}
+
+// Union returns a new Set of the cells in either set.
+func (s *Set) Union(o *Set) *Set {
+	return &Set{ranges: mergeRanges(slices.Concat(s.ranges, o.ranges))}
+}
+
+// Intersect returns a new Set of the area covered by both sets.
+// A cell in one set overlapping a finer cell in the other contributes only the finer cell.
+func (s *Set) Intersect(o *Set) *Set {
+	s.Normalize()
+	o.Normalize()
+
+	res := &Set{}
+	for i, j := 0, 0; i < len(s.ranges) && j < len(o.ranges); {
+		a, b := s.ranges[i], o.ranges[j]
+		if lo, hi := max(a.Min, b.Min), min(a.Max, b.Max); lo <= hi {
+			res.ranges = append(res.ranges, IntRange{Min: lo, Max: hi})
+		}
+		if a.Max < b.Max {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// Difference returns a new Set of the area covered by s and not by o.
+// Removing a finer cell from a coarser cell leaves the remaining siblings at each level.
+func (s *Set) Difference(o *Set) *Set {
+	s.Normalize()
+	o.Normalize()
+
+	res := &Set{}
+	j := 0
+	for _, a := range s.ranges {
+		// Skip the ranges of o ending before a, then cut each overlapping range out of a.
+		for j < len(o.ranges) && o.ranges[j].Max < a.Min {
+			j++
+		}
+		lo, empty := a.Min, false
+		for k := j; k < len(o.ranges) && o.ranges[k].Min <= a.Max; k++ {
+			b := o.ranges[k]
+			if b.Min > lo {
+				res.ranges = append(res.ranges, IntRange{Min: lo, Max: b.Min - 1})
+			}
+			if b.Max >= a.Max {
+				empty = true
+				break
+			}
+			lo = max(lo, b.Max+1)
+		}
+		if !empty {
+			res.ranges = append(res.ranges, IntRange{Min: lo, Max: a.Max})
+		}
+	}
+	return res
+}
+
+// Len returns the number of cells in the normalized set.
+func (s *Set) Len() int {
+	n := 0
+	s.Range(func(string) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Area returns the surface area of the set in square meters, as returned by Box.Area.
+func (s *Set) Area() float64 {
+	area := 0.0
+	s.Range(func(hash string) bool {
+		area += DecodeBox(hash).Area()
+		return true
+	})
+	return area
+}
+
+// Ranges returns the normalized ranges of 64-bit geohash integers covered by the set, in Z-order.
+// These can be passed to WhereRanges to query a BIGINT column of 64-bit IntHash values.
+func (s *Set) Ranges() []IntRange {
+	s.Normalize()
+	return slices.Clone(s.ranges)
+}
+
+// Range calls fn with the geohash string of every cell of the normalized set in Z-order.
+// Each range is split into the fewest cells, so every cell is as coarse as possible. Iteration stops when fn returns false.
+func (s *Set) Range(fn func(hash string) bool) {
+	s.Normalize()
+	for _, r := range s.ranges {
+		for lo := r.Min; ; {
+			p := rangePrecision(lo, r.Max)
+			last := lo | cellMask(p)
+
+			hash := ""
+			if p > 0 {
+				hash = EncodeIntToStr(lo>>(64-p*5), p)
+			}
+			if !fn(hash) {
+				return
+			}
+
+			if last >= r.Max {
+				break
+			}
+			lo = last + 1
+		}
+	}
+}
+
+// find returns the index of the range containing the 64-bit geohash integer hash, and whether one exists.
+func (s *Set) find(hash uint64) (int, bool) {
+	s.Normalize()
+	i, found := slices.BinarySearchFunc(s.ranges, hash, func(r IntRange, h uint64) int {
+		switch {
+		case r.Max < h:
+			return -1
+		case r.Min > h:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return i, found
+}
+
+// hashRange returns the range of 64-bit geohash integers within the cell of h.
+func hashRange(h Hash) IntRange {
+	if h.bits == 0 {
+		return IntRange{Min: 0, Max: math.MaxUint64}
+	}
+	min := h.value << (64 - h.bits)
+	return IntRange{Min: min, Max: min | cellMask(h.bits/5)}
+}
+
+// cellMask returns the low bits of the 64-bit geohash integers that vary within a cell of a character precision.
+func cellMask(precision int) uint64 {
+	if precision == 0 {
+		return math.MaxUint64
+	}
+	return 1<<(64-precision*5) - 1
+}
+
+// rangePrecision returns the precision of the largest cell starting at lo that ends at or before hi.
+func rangePrecision(lo, hi uint64) int {
+	for p := 0; p < precisionMax; p++ {
+		mask := cellMask(p)
+		if lo&mask == 0 && hi-lo >= mask {
+			return p
+		}
+	}
+	return precisionMax
+}
@@ -0,0 +1,156 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// setHashes returns the cells of s in iteration order.
+func setHashes(s *Set) []string {
+	hashes := []string{}
+	s.Range(func(hash string) bool {
+		hashes = append(hashes, hash)
+		return true
+	})
+	return hashes
+}
+
+// children returns the 32 children of hash.
+func children(hash string) []string {
+	c := make([]string, len(base32))
+	for i := range base32 {
+		c[i] = hash + base32[i:i+1]
+	}
+	return c
+}
+
+func TestSetNormalize(t *testing.T) {
+	testCases := []struct {
+		add, want []string
+	}{
+		// Children covered by an ancestor are removed.
+		{[]string{"dqcjq", "dqc", "dqcjqc"}, []string{"dqc"}},
+		// Cells are iterated in Z-order.
+		{[]string{"u", "9", "dqcjq"}, []string{"9", "dqcjq", "u"}},
+		// A full sibling group is merged into its parent, recursively.
+		{append(children("dqcj"), children("dqcjq")...), []string{"dqcj"}},
+		// A partial sibling group keeps each sibling.
+		{children("dqcj")[:3], []string{"dqcj0", "dqcj1", "dqcj2"}},
+		// The empty string is the whole world.
+		{[]string{"", "dqcjq"}, []string{""}},
+	}
+
+	for _, c := range testCases {
+		s, err := NewSet(c.add...)
+		if err != nil {
+			t.Fatalf("NewSet = %s", err.Error())
+		}
+		if res := setHashes(s); !slices.Equal(res, c.want) {
+			t.Errorf("Range = %v, want %v", res, c.want)
+		}
+		if s.Len() != len(c.want) {
+			t.Errorf("Len = %d, want %d", s.Len(), len(c.want))
+		}
+	}
+
+	if _, err := NewSet("dqcja"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("NewSet = %v, want %v", err, ErrInvalidHash)
+	}
+
+	var s Set
+	if s.Len() != 0 || s.Contains("d") || s.ContainsPoint(0, 0) || s.Area() != 0 {
+		t.Errorf("Set{} is not empty")
+	}
+}
+
+func TestSetContains(t *testing.T) {
+	s, _ := NewSet("dqcj", "u4pruydqqvj")
+
+	for _, hash := range []string{"dqcj", "dqcjq", "dqcjqcp", "u4pruydqqvj", "u4pruydqqvjb"} {
+		if !s.Contains(hash) {
+			t.Errorf("Contains(%s) = false, want true", hash)
+		}
+	}
+	for _, hash := range []string{"dqc", "dqcm", "u4pruydqqv", "dqcja", ""} {
+		if s.Contains(hash) {
+			t.Errorf("Contains(%s) = true, want false", hash)
+		}
+	}
+
+	lat, lng := DecodeBox("dqcjq").Center()
+	if !s.ContainsPoint(lat, lng) {
+		t.Errorf("ContainsPoint(%f, %f) = false, want true", lat, lng)
+	}
+	lat, lng = DecodeBox("dqcm").Center()
+	if s.ContainsPoint(lat, lng) {
+		t.Errorf("ContainsPoint(%f, %f) = true, want false", lat, lng)
+	}
+}
+
+func TestSetContainsPointMaxEdge(t *testing.T) {
+	s, _ := NewSet("zzz")
+
+	// Points on the max edges are within the northeast corner cell rather than overflowing to "000".
+	for _, p := range []Point{{90, 180}, {89.9, 180}, {90, 179.9}} {
+		if !s.ContainsPoint(p.Lat, p.Lng) {
+			t.Errorf("ContainsPoint(%f, %f) = false, want true", p.Lat, p.Lng)
+		}
+	}
+	if s, _ := NewSet("000"); s.ContainsPoint(90, 180) {
+		t.Errorf("ContainsPoint(90, 180) = true, want false")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a, _ := NewSet("dqcj", "9q")
+	b, _ := NewSet("dqcjq", "dqcm", "9q8")
+
+	if res := setHashes(a.Union(b)); !slices.Equal(res, []string{"9q", "dqcj", "dqcm"}) {
+		t.Errorf("Union = %v", res)
+	}
+	if res := setHashes(a.Intersect(b)); !slices.Equal(res, []string{"9q8", "dqcjq"}) {
+		t.Errorf("Intersect = %v", res)
+	}
+
+	// Removing a child leaves its 31 siblings.
+	want := slices.DeleteFunc(children("9q"), func(h string) bool { return h == "9q8" })
+	want = append(want, slices.DeleteFunc(children("dqcj"), func(h string) bool { return h == "dqcjq" })...)
+	if res := setHashes(a.Difference(b)); !slices.Equal(res, want) {
+		t.Errorf("Difference = %v, want %v", res, want)
+	}
+	if res := setHashes(b.Difference(a)); !slices.Equal(res, []string{"dqcm"}) {
+		t.Errorf("Difference = %v, want [dqcm]", res)
+	}
+
+	// Adding back the difference restores the set.
+	if res := setHashes(a.Difference(b).Union(a.Intersect(b))); !slices.Equal(res, []string{"9q", "dqcj"}) {
+		t.Errorf("Union = %v, want [9q dqcj]", res)
+	}
+}
+
+func TestSetArea(t *testing.T) {
+	s, _ := NewSet("")
+	if area := s.Area(); math.Abs(area-4*math.Pi*earthRadius*earthRadius) > 1 {
+		t.Errorf("Area = %f, want the surface of the Earth", area)
+	}
+
+	s, _ = NewSet(children("dqcj")...)
+	want := DecodeBox("dqcj").Area()
+	if area := s.Area(); math.Abs(area-want) > 1e-6*want {
+		t.Errorf("Area = %f, want %f", area, want)
+	}
+}
+
+func BenchmarkSetContainsPoint(b *testing.B) {
+	s := &Set{}
+	for _, hash := range CoverBox(benchRangesBox, 7) {
+		s.Add(hash)
+	}
+	s.Normalize()
+
+	for n := 0; n < b.N; n++ {
+		s.ContainsPoint(testLat, testLng)
+	}
+}
@@ -0,0 +1,309 @@
+package geohash
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidRoutes is returned by Sharder.UnmarshalJSON for routes that overlap or do not cover the world.
+	ErrInvalidRoutes = errors.New("geohash: routes do not partition the world")
+
+	// ErrNoRoute is returned by Sharder methods for a prefix that is not a route or is within a route,
+	// and by Shard for a Sharder without routes.
+	ErrNoRoute = errors.New("geohash: no route for prefix")
+
+	// ErrShardSpan is returned by Sharder.ShardOfHash for a cell containing routes of several shards.
+	ErrShardSpan = errors.New("geohash: cell spans several shards")
+)
+
+// shardPrefixMax is the longest prefix a Sharder is created with, giving 32^4 (1,048,576) routes.
+const shardPrefixMax = 4
+
+// Route maps the cells beginning with a geohash prefix to a shard.
+type Route struct {
+	Prefix string `json:"prefix"`
+	Shard  int    `json:"shard"`
+}
+
+// Sharder routes locations to shards by geohash prefix.
+// The routes partition the world: every location falls within exactly one route prefix, and prefixes may be of mixed length.
+// Hot prefixes can be split into their 32 children and cold prefixes merged back into their parent,
+// so busy regions are spread over more shards while sparse regions share one.
+// A Sharder is safe for concurrent use. Lookups take a read lock while changes to the routes take a write lock.
+// Create a Sharder with NewSharder, or load a routing table into a zero Sharder with UnmarshalJSON.
+type Sharder struct {
+	mu     sync.RWMutex
+	routes map[string]int
+
+	// inner counts the routes beneath each strict prefix of a route, used to descend to the routes within a cell.
+	inner map[string]int
+}
+
+// NewSharder returns a Sharder with a route for every prefix of the provided length, assigned to the provided number of shards.
+// Routes are assigned in contiguous runs of Z-order, so neighboring prefixes usually share a shard.
+// Acceptable prefix lengths are 1 to 4 characters and the number of shards is at least 1.
+func NewSharder(prefixLength, shards int) *Sharder {
+	prefixLength = validate(precisionMin, shardPrefixMax, prefixLength)
+	shards = max(1, shards)
+
+	s := &Sharder{routes: map[string]int{}, inner: map[string]int{}}
+	n := 1 << (5 * prefixLength)
+	for i := 0; i < n; i++ {
+		s.add(EncodeIntToStr(uint64(i), prefixLength), i*shards/n)
+	}
+	return s
+}
+
+// Shard returns the shard of the lat, lng coordinates.
+// Points on the max edges of 90 and 180 fall within the northernmost and easternmost routes.
+// The routes partition the world, so ErrNoRoute is only returned by a zero Sharder that has not loaded any routes.
+func (s *Sharder) Shard(lat, lng float64) (int, error) {
+	hash := EncodeIntToStr(encodeIntClamp(lat, lng, precisionMax*5), precisionMax)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	shard, ok := s.route(hash)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNoRoute, hash)
+	}
+	return shard, nil
+}
+
+// ShardOfHash returns the shard of a geohash cell.
+// A cell coarser than the routes within it returns their shard when they share one, and ErrShardSpan otherwise.
+// ErrInvalidHash is returned for invalid geohash strings.
+func (s *Sharder) ShardOfHash(hash string) (int, error) {
+	if err := Base32Alphabet.Validate(hash); err != nil {
+		return 0, err
+	}
+
+	shards := s.ShardsForCells([]string{hash})
+	if len(shards) != 1 {
+		return 0, fmt.Errorf("%w: %s", ErrShardSpan, hash)
+	}
+	return shards[0], nil
+}
+
+// ShardsForCells returns the sorted shards a query covering the cells must fan out to.
+// This includes the shard of the route containing each cell, and the shards of every route within cells coarser than the routes.
+// Invalid geohash strings are ignored.
+func (s *Sharder) ShardsForCells(cells []string) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := map[int]bool{}
+	for _, hash := range cells {
+		if Base32Alphabet.Validate(hash) != nil {
+			continue
+		}
+		if shard, ok := s.route(hash); ok {
+			seen[shard] = true
+			continue
+		}
+		s.walk(hash, func(prefix string) bool { return true }, seen)
+	}
+	return sortedShards(seen)
+}
+
+// ShardsForBox returns the sorted shards a query of box must fan out to, which are the shards of the routes overlapping box.
+// Routes are found by descending from the coarsest prefixes into those overlapping box, so the cost depends on the routes overlapping box.
+// A box crossing the antimeridian (MinLng > MaxLng) is supported.
+func (s *Sharder) ShardsForBox(box Box) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	boxes := splitBox(box)
+	seen := map[int]bool{}
+	s.walk("", func(prefix string) bool {
+		cell := DecodeBox(prefix)
+		for _, b := range boxes {
+			if b.MinLat < cell.MaxLat && cell.MinLat <= b.MaxLat && b.MinLng < cell.MaxLng && cell.MinLng <= b.MaxLng {
+				return true
+			}
+		}
+		return false
+	}, seen)
+	return sortedShards(seen)
+}
+
+// Split replaces the route of prefix with routes for its 32 children, assigned to the same shard.
+// Use Assign to move children to other shards. ErrNoRoute is returned if prefix is not a route,
+// and ErrInvalidHash if prefix is already 12 characters.
+func (s *Sharder) Split(prefix string) error {
+	if len(prefix) >= precisionMax {
+		return fmt.Errorf("%w: cannot split %s", ErrInvalidHash, prefix)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shard, ok := s.routes[prefix]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoRoute, prefix)
+	}
+	s.remove(prefix)
+	for i := 0; i < len(base32); i++ {
+		s.add(prefix+string(base32[i]), shard)
+	}
+	return nil
+}
+
+// Merge replaces every route within prefix with a single route of prefix assigned to shard.
+// A prefix that is already a route is reassigned. ErrNoRoute is returned if prefix is within a coarser route.
+func (s *Sharder) Merge(prefix string, shard int) error {
+	if err := Base32Alphabet.Validate(prefix); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.routes[prefix]; !ok && s.inner[prefix] == 0 {
+		return fmt.Errorf("%w: %s is within a route", ErrNoRoute, prefix)
+	}
+
+	within := []string{}
+	for p := range s.routes {
+		if strings.HasPrefix(p, prefix) {
+			within = append(within, p)
+		}
+	}
+	for _, p := range within {
+		s.remove(p)
+	}
+	s.add(prefix, shard)
+	return nil
+}
+
+// Assign moves the route of prefix to shard. ErrNoRoute is returned if prefix is not a route.
+func (s *Sharder) Assign(prefix string, shard int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.routes[prefix]; !ok {
+		return fmt.Errorf("%w: %s", ErrNoRoute, prefix)
+	}
+	s.routes[prefix] = shard
+	return nil
+}
+
+// Routes returns the routes ordered by prefix, which is Z-order.
+func (s *Sharder) Routes() []Route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routes := make([]Route, 0, len(s.routes))
+	for prefix, shard := range s.routes {
+		routes = append(routes, Route{Prefix: prefix, Shard: shard})
+	}
+	slices.SortFunc(routes, func(a, b Route) int {
+		return cmp.Compare(a.Prefix, b.Prefix)
+	})
+	return routes
+}
+
+// MarshalJSON encodes the routing table as a JSON array of routes ordered by prefix.
+func (s *Sharder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Routes())
+}
+
+// UnmarshalJSON replaces the routing table with a JSON array of routes encoded by MarshalJSON.
+// ErrInvalidHash is returned for invalid prefixes, and ErrInvalidRoutes for routes that overlap or do not cover the world.
+// The routing table is unchanged if an error is returned.
+func (s *Sharder) UnmarshalJSON(data []byte) error {
+	var routes []Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return err
+	}
+
+	// Base32 characters are in ascending ASCII order, so sorting by string sorts in Z-order,
+	// and a prefix overlapping another route is followed immediately by a route it is a prefix of.
+	slices.SortFunc(routes, func(a, b Route) int {
+		return cmp.Compare(a.Prefix, b.Prefix)
+	})
+
+	// Without overlaps, the routes partition the world when their cells add up to the whole world of 32^12 cells.
+	var total uint64
+	for i, r := range routes {
+		if err := Base32Alphabet.Validate(r.Prefix); err != nil || len(r.Prefix) > precisionMax {
+			return fmt.Errorf("%w: route %q", ErrInvalidHash, r.Prefix)
+		}
+		if i > 0 && strings.HasPrefix(r.Prefix, routes[i-1].Prefix) {
+			return fmt.Errorf("%w: %q overlaps %q", ErrInvalidRoutes, routes[i-1].Prefix, r.Prefix)
+		}
+		total += 1 << (5 * (precisionMax - len(r.Prefix)))
+	}
+	if total != 1<<(5*precisionMax) {
+		return fmt.Errorf("%w: routes cover %d of %d cells", ErrInvalidRoutes, total, uint64(1)<<(5*precisionMax))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes, s.inner = map[string]int{}, map[string]int{}
+	for _, r := range routes {
+		s.add(r.Prefix, r.Shard)
+	}
+	return nil
+}
+
+// route returns the shard of the route that is hash or a prefix of hash.
+func (s *Sharder) route(hash string) (int, bool) {
+	for i := len(hash); i >= 0; i-- {
+		if shard, ok := s.routes[hash[:i]]; ok {
+			return shard, true
+		}
+	}
+	return 0, false
+}
+
+// walk adds the shards of the routes within prefix to seen, descending only into prefixes for which descend returns true.
+func (s *Sharder) walk(prefix string, descend func(string) bool, seen map[int]bool) {
+	if !descend(prefix) {
+		return
+	}
+	if shard, ok := s.routes[prefix]; ok {
+		seen[shard] = true
+		return
+	}
+	if s.inner[prefix] == 0 {
+		return
+	}
+	for i := 0; i < len(base32); i++ {
+		s.walk(prefix+string(base32[i]), descend, seen)
+	}
+}
+
+// add adds a route and counts it beneath each of its strict prefixes.
+func (s *Sharder) add(prefix string, shard int) {
+	s.routes[prefix] = shard
+	for i := 0; i < len(prefix); i++ {
+		s.inner[prefix[:i]]++
+	}
+}
+
+// remove removes a route and its counts beneath each of its strict prefixes.
+func (s *Sharder) remove(prefix string) {
+	delete(s.routes, prefix)
+	for i := 0; i < len(prefix); i++ {
+		if s.inner[prefix[:i]]--; s.inner[prefix[:i]] == 0 {
+			delete(s.inner, prefix[:i])
+		}
+	}
+}
+
+// sortedShards returns the shards of seen in ascending order.
+func sortedShards(seen map[int]bool) []int {
+	shards := make([]int, 0, len(seen))
+	for shard := range seen {
+		shards = append(shards, shard)
+	}
+	slices.Sort(shards)
+	return shards
+}
@@ -0,0 +1,186 @@
+package geohash
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSharder(t *testing.T) {
+	s := NewSharder(1, 4)
+	if n := len(s.Routes()); n != 32 {
+		t.Fatalf("Routes = %d routes, want 32", n)
+	}
+
+	// 32 prefixes over 4 shards in runs of 8: 0-7, 8-g, h-r, s-z.
+	tests := []struct {
+		lat, lng float64
+		hash     string
+		shard    int
+	}{
+		{40.7128, -74.0060, "dr5ru", 1},
+		{51.5074, -0.1278, "gcpvj", 1},
+		{-33.8688, 151.2093, "r3gx2", 2},
+		{35.6762, 139.6503, "xn774", 3},
+		{-45, -170, "0", 0},
+		// The max edges fall within the northeast route rather than overflowing to the southwest.
+		{90, 180, "z", 3},
+	}
+	for _, test := range tests {
+		if res, err := s.Shard(test.lat, test.lng); err != nil || res != test.shard {
+			t.Errorf("Shard(%f, %f) = %d, %v, want %d", test.lat, test.lng, res, err, test.shard)
+		}
+		if res, err := s.ShardOfHash(test.hash); err != nil || res != test.shard {
+			t.Errorf("ShardOfHash(%s) = %d, %v, want %d", test.hash, res, err, test.shard)
+		}
+	}
+
+	if _, err := s.ShardOfHash(""); !errors.Is(err, ErrShardSpan) {
+		t.Errorf("ShardOfHash = %v, want %v", err, ErrShardSpan)
+	}
+	if _, err := s.ShardOfHash("dra"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("ShardOfHash = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestSharderSplitMerge(t *testing.T) {
+	s := NewSharder(2, 1)
+
+	// Split the hot dr into 32 children, then split dr5 again and move Manhattan to its own shard.
+	if err := s.Split("dr"); err != nil {
+		t.Fatalf("Split = %s", err.Error())
+	}
+	if err := s.Split("dr5"); err != nil {
+		t.Fatalf("Split = %s", err.Error())
+	}
+	if err := s.Assign("dr5r", 1); err != nil {
+		t.Fatalf("Assign = %s", err.Error())
+	}
+	if n := len(s.Routes()); n != 1024-1+32-1+32 {
+		t.Errorf("Routes = %d routes, want %d", n, 1024-1+32-1+32)
+	}
+
+	if res, err := s.Shard(40.7128, -74.0060); err != nil || res != 1 {
+		t.Errorf("Shard = %d, %v, want 1", res, err)
+	}
+	if res, err := s.ShardOfHash("dr5ru"); err != nil || res != 1 {
+		t.Errorf("ShardOfHash = %d, %v, want 1", res, err)
+	}
+	if _, err := s.ShardOfHash("dr5"); !errors.Is(err, ErrShardSpan) {
+		t.Errorf("ShardOfHash = %v, want %v", err, ErrShardSpan)
+	}
+	if res := s.ShardsForCells([]string{"dr", "9q"}); !slices.Equal(res, []int{0, 1}) {
+		t.Errorf("ShardsForCells = %v, want [0 1]", res)
+	}
+	if res := s.ShardsForCells([]string{"dr5ru", "dr5ruj", "9q"}); !slices.Equal(res, []int{0, 1}) {
+		t.Errorf("ShardsForCells = %v, want [0 1]", res)
+	}
+	if res := s.ShardsForCells([]string{"9q", "invalid"}); !slices.Equal(res, []int{0}) {
+		t.Errorf("ShardsForCells = %v, want [0]", res)
+	}
+
+	for _, err := range []error{s.Split("dr"), s.Assign("dr", 2), s.Merge("dr5ru", 2), s.Split("dr5rujjjjjjj")} {
+		if !errors.Is(err, ErrNoRoute) && !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("error = %v, want %v or %v", err, ErrNoRoute, ErrInvalidHash)
+		}
+	}
+
+	// Merging dr brings its 63 routes back into one.
+	if err := s.Merge("dr", 2); err != nil {
+		t.Fatalf("Merge = %s", err.Error())
+	}
+	if n := len(s.Routes()); n != 1024 {
+		t.Errorf("Routes = %d routes, want 1024", n)
+	}
+	if res, err := s.Shard(40.7128, -74.0060); err != nil || res != 2 {
+		t.Errorf("Shard = %d, %v, want 2", res, err)
+	}
+
+	if err := s.Merge("", 3); err != nil {
+		t.Fatalf("Merge = %s", err.Error())
+	}
+	if res := s.Routes(); !slices.Equal(res, []Route{{Prefix: "", Shard: 3}}) {
+		t.Errorf("Routes = %v, want the whole world on shard 3", res)
+	}
+	if res, err := s.ShardOfHash(""); err != nil || res != 3 {
+		t.Errorf("ShardOfHash = %d, %v, want 3", res, err)
+	}
+}
+
+func TestSharderBox(t *testing.T) {
+	s := NewSharder(1, 32)
+	s.Split("d")
+	s.Assign("dr", 100)
+
+	tests := []struct {
+		box  Box
+		want []int
+	}{
+		// Manhattan is within dr.
+		{Box{MinLat: 40.70, MaxLat: 40.80, MinLng: -74.02, MaxLng: -73.93}, []int{100}},
+		// New York to Florida spans dq, dr, dj, dn and dp.
+		{Box{MinLat: 28, MaxLat: 40.8, MinLng: -80, MaxLng: -74}, []int{12, 100}},
+		// Crossing the antimeridian between 2 and r, and 8 and x.
+		{Box{MinLat: -10, MaxLat: 10, MinLng: 170, MaxLng: -170}, []int{2, 8, 23, 29}},
+	}
+
+	for _, test := range tests {
+		if res := s.ShardsForBox(test.box); !slices.Equal(res, test.want) {
+			t.Errorf("ShardsForBox(%+v) = %v, want %v", test.box, res, test.want)
+		}
+	}
+}
+
+func TestSharderJSON(t *testing.T) {
+	s := NewSharder(1, 3)
+	s.Split("9")
+	s.Assign("9q", 7)
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("MarshalJSON = %s", err.Error())
+	}
+
+	var res Sharder
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatalf("UnmarshalJSON = %s", err.Error())
+	}
+	if !slices.Equal(res.Routes(), s.Routes()) {
+		t.Errorf("UnmarshalJSON = %v, want %v", res.Routes(), s.Routes())
+	}
+	if shard, err := res.Shard(37.7749, -122.4194); err != nil || shard != 7 {
+		t.Errorf("Shard = %d, %v, want 7", shard, err)
+	}
+
+	tests := []struct {
+		data string
+		err  error
+	}{
+		{`[{"prefix":"0","shard":0}]`, ErrInvalidRoutes},
+		{`[{"prefix":"","shard":0},{"prefix":"0","shard":0}]`, ErrInvalidRoutes},
+		{`[{"prefix":"a","shard":0}]`, ErrInvalidHash},
+	}
+	for _, test := range tests {
+		if err := res.UnmarshalJSON([]byte(test.data)); !errors.Is(err, test.err) {
+			t.Errorf("UnmarshalJSON(%s) = %v, want %v", test.data, err, test.err)
+		}
+	}
+	if !slices.Equal(res.Routes(), s.Routes()) {
+		t.Errorf("UnmarshalJSON changed routes after an error")
+	}
+}
+
+func TestSharderNoRoutes(t *testing.T) {
+	var s Sharder
+	if res, err := s.Shard(40.7128, -74.0060); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("Shard = %d, %v, want %v", res, err, ErrNoRoute)
+	}
+}
+
+func BenchmarkSharderShard(b *testing.B) {
+	s := NewSharder(3, 64)
+	for n := 0; n < b.N; n++ {
+		s.Shard(testLat, testLng)
+	}
+}
@@ -0,0 +1,75 @@
+package geohash
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+)
+
+// Spiral returns an iterator over the cells of the provided character precision in order of increasing distance from lat, lng.
+// Each cell is paired with the distance in meters from lat, lng to its nearest point, so the cell containing lat, lng is first with 0.
+// Cells are found best first from the neighbors of the cells already yielded, so a search can expand gradually
+// and stop once the distance exceeds its radius. Acceptable precision values are 1 to 12 characters.
+// Iterating to the end visits every cell in the world.
+func Spiral(lat, lng float64, precision int) iter.Seq2[string, float64] {
+	precision = validate(precisionMin, precisionMax, precision)
+
+	return func(yield func(string, float64) bool) {
+		for hash, d := range SpiralInt(lat, lng, precision*5) {
+			if !yield(EncodeIntToStr(hash, precision), d) {
+				return
+			}
+		}
+	}
+}
+
+// SpiralInt returns an iterator over the geohash integers of the cells of the provided bit precision in order of increasing distance from lat, lng.
+// This is the integer form of Spiral using the layout of EncodeIntPrecision. Acceptable bit values are 1 to 64.
+func SpiralInt(lat, lng float64, bits int) iter.Seq2[uint64, float64] {
+	bits = validate(bitsMin, bitsMax, bits)
+	p := Point{Lat: lat, Lng: lng}
+
+	return func(yield func(uint64, float64) bool) {
+		start := encodeIntClamp(lat, lng, bits)
+		queue := &spiralQueue{{hash: start}}
+		seen := map[uint64]bool{start: true}
+
+		// The cells within any distance of the point are connected, so expanding the nearest cell first yields them in order.
+		for queue.Len() > 0 {
+			c := heap.Pop(queue).(spiralCell)
+			if !yield(c.hash, c.dist) {
+				return
+			}
+
+			gridRing(c.hash, bits, 1, seen, func(n uint64) bool {
+				heap.Push(queue, spiralCell{hash: n, dist: boxDistance(p, WGS84.DecodeIntBox(n, bits))})
+				return true
+			})
+		}
+	}
+}
+
+// spiralCell is a cell queued by SpiralInt with its distance from the point.
+type spiralCell struct {
+	hash uint64
+	dist float64
+}
+
+// spiralQueue is a min heap of cells by distance, with ties ordered by geohash integer.
+type spiralQueue []spiralCell
+
+func (q spiralQueue) Len() int { return len(q) }
+func (q spiralQueue) Less(i, j int) bool {
+	if q[i].dist != q[j].dist {
+		return q[i].dist < q[j].dist
+	}
+	return cmp.Less(q[i].hash, q[j].hash)
+}
+func (q spiralQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *spiralQueue) Push(x any)   { *q = append(*q, x.(spiralCell)) }
+func (q *spiralQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
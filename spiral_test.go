@@ -0,0 +1,94 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpiral(t *testing.T) {
+	lat, lng := 40.7580, -73.9855
+	first := true
+	last := 0.0
+	seen := map[string]bool{}
+
+	for hash, d := range Spiral(lat, lng, 6) {
+		if first {
+			if want := EncodePrecision(lat, lng, 6); hash != want || d != 0 {
+				t.Errorf("Spiral = %s, %f first, want %s, 0", hash, d, want)
+			}
+			first = false
+		}
+		if d < last {
+			t.Errorf("Spiral = %s at %f, after %f", hash, d, last)
+		}
+		if seen[hash] {
+			t.Errorf("Spiral = %s yielded twice", hash)
+		}
+		if want := boxDistance(Point{lat, lng}, DecodeBox(hash)); d != want {
+			t.Errorf("Spiral = %s at %f, want %f", hash, d, want)
+		}
+		last = d
+		seen[hash] = true
+
+		if d > 3000 {
+			break
+		}
+	}
+
+	// Every cell within the disk of 3km is found before stopping.
+	for hash := range Disk(EncodePrecision(lat, lng, 6), 6) {
+		if d := boxDistance(Point{lat, lng}, DecodeBox(hash)); d <= 3000 && !seen[hash] {
+			t.Errorf("Spiral did not yield %s at %f", hash, d)
+		}
+	}
+
+	// Iterating to the end visits every cell in the world.
+	n := 0
+	for range Spiral(-89, 179, 2) {
+		n++
+	}
+	if n != 1024 {
+		t.Errorf("Spiral = %d cells, want 1024", n)
+	}
+}
+
+func TestSpiralMaxEdge(t *testing.T) {
+	for hash, d := range Spiral(90, 180, 4) {
+		if hash != "zzzz" || d != 0 {
+			t.Errorf("Spiral = %s, %f first, want zzzz, 0", hash, d)
+		}
+		break
+	}
+}
+
+func TestBoxDistance(t *testing.T) {
+	box := Box{MinLat: 0, MaxLat: 1, MinLng: 0, MaxLng: 1}
+
+	testCases := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{0.5, 0.5}, 0},
+		{Point{2, 0.5}, metersPerDegree},
+		{Point{-1, 0.5}, metersPerDegree},
+		{Point{0.5, 2}, haversine(Point{0.5, 2}, Point{0.5, 1})},
+		{Point{0.5, -359.5}, 0},
+	}
+
+	for _, c := range testCases {
+		if res := boxDistance(c.p, box); math.Abs(res-c.want) > 1 {
+			t.Errorf("boxDistance(%+v) = %f, want %f", c.p, res, c.want)
+		}
+	}
+}
+
+func BenchmarkSpiral(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		i := 0
+		for range Spiral(testLat, testLng, 7) {
+			if i++; i == 100 {
+				break
+			}
+		}
+	}
+}
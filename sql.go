@@ -0,0 +1,171 @@
+package geohash
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Value implements driver.Valuer, storing the geohash string in a TEXT column.
+// Convert to IntHash to store the geohash integer in a BIGINT column instead.
+func (h Hash) Value() (driver.Value, error) {
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan implements sql.Scanner, reading a geohash string from a TEXT column or a geohash integer from a BIGINT column.
+// Integers are assumed to use the EncodeInt layout of 64 bits. Use HashColumn to scan integers of another bit precision.
+// A NULL column results in the zero value Hash.
+func (h *Hash) Scan(src any) error {
+	return scan(h, src, bitsMax)
+}
+
+// Value implements driver.Valuer, storing the geohash integer in a BIGINT column.
+// SQL integers are signed, so the uint64 is stored as the int64 with the same bits.
+// Hashes with the high bit set, such as 64-bit hashes in the eastern hemisphere, are stored as negative values.
+func (h IntHash) Value() (driver.Value, error) {
+	return int64(h.value), nil
+}
+
+// Scan implements sql.Scanner, reading a geohash integer from a BIGINT column or a geohash string from a TEXT column.
+// Integers are assumed to use the EncodeInt layout of 64 bits. Use HashColumn to scan integers of another bit precision.
+// A NULL column results in the zero value IntHash.
+func (h *IntHash) Scan(src any) error {
+	return scan((*Hash)(h), src, bitsMax)
+}
+
+// HashColumn is an sql.Scanner reading a geohash integer of a fixed bit precision from a BIGINT column into Hash.
+// The precision belongs to the column rather than the scanned value,
+// so reusing a HashColumn across rows gives the same result regardless of the values or NULLs scanned before.
+// A Bits of 0 assumes the EncodeInt layout of 64 bits. Geohash strings and NULL columns are scanned as by Hash.Scan.
+//
+// Example: col := HashColumn{Bits: 60}; rows.Scan(&col)
+type HashColumn struct {
+	Hash Hash
+	Bits int
+}
+
+// Scan implements sql.Scanner.
+func (c *HashColumn) Scan(src any) error {
+	bits := c.Bits
+	if bits == 0 {
+		bits = bitsMax
+	}
+	return scan(&c.Hash, src, validate(bitsMin, bitsMax, bits))
+}
+
+// scan parses src into h, reading integers at the provided bit precision.
+func scan(h *Hash, src any, bits int) error {
+	switch v := src.(type) {
+	case nil:
+		*h = Hash{}
+		return nil
+	case string:
+		return h.UnmarshalText([]byte(v))
+	case []byte:
+		return h.UnmarshalText(v)
+	case int64:
+		value := uint64(v)
+		if bits < bitsMax && value>>bits != 0 {
+			return fmt.Errorf("%w: value exceeds %d bits", ErrInvalidHash, bits)
+		}
+		*h = Hash{value: value, bits: bits}
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidHash, src)
+	}
+}
+
+// boxRangesMaxCells is the most cells BoxRanges and HilbertRanges visit.
+// Larger boxes are covered at a coarser bit precision instead, bounding the time and memory a single call uses.
+const boxRangesMaxCells = 1 << 16
+
+// IntRange is an inclusive range of geohash integers.
+type IntRange struct {
+	Min, Max uint64
+}
+
+// BoxRanges returns the ranges of geohash integers stored at columnBits precision that fall within cells of bits precision overlapping box.
+// Cells are visited using the integer grid, converted to the column precision, sorted, and contiguous ranges are merged.
+// Lower bits results in fewer ranges that cover more area outside the box, so results should still be filtered by coordinates.
+// Acceptable bit values are 1 to 64 and bits is limited to columnBits.
+// If more than 65536 cells of bits precision overlap box, bits is lowered until no more than 65536 do.
+// The box is assumed not to cross the antimeridian (MinLng <= MaxLng).
+func BoxRanges(box Box, bits, columnBits int) []IntRange {
+	columnBits = validate(bitsMin, bitsMax, columnBits)
+	bits = boxBits(box, validate(bitsMin, columnBits, bits), boxRangesMaxCells)
+	shift := columnBits - bits
+
+	ranges := []IntRange{}
+	boxCells(box, bits, func(hash uint64) bool {
+		r := IntRange{Min: hash << shift, Max: hash<<shift | (1<<shift - 1)}
+		ranges = append(ranges, r)
+		return true
+	})
+
+	return mergeRanges(ranges)
+}
+
+// mergeRanges sorts ranges and merges those that overlap or are contiguous, reusing the backing array of ranges.
+func mergeRanges(ranges []IntRange) []IntRange {
+	slices.SortFunc(ranges, func(a, b IntRange) int {
+		return cmp.Compare(a.Min, b.Min)
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && (merged[n-1].Max == math.MaxUint64 || merged[n-1].Max+1 >= r.Min) {
+			merged[n-1].Max = max(merged[n-1].Max, r.Max)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// Placeholder returns the SQL bind parameter for the nth (1-based) argument.
+type Placeholder func(n int) string
+
+var (
+	// PlaceholderQuestion is the ? parameter style used by MySQL and SQLite.
+	PlaceholderQuestion Placeholder = func(int) string { return "?" }
+
+	// PlaceholderDollar is the $n parameter style used by PostgreSQL.
+	PlaceholderDollar Placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// WhereRanges returns a WHERE clause expression and its arguments matching a BIGINT column storing IntHash values within ranges.
+// As IntHash is stored as a signed int64, a range spanning the high bit is split into a positive and a negative range.
+// An empty ranges slice produces an expression that matches no rows.
+// Example: (geohash BETWEEN ? AND ? OR geohash BETWEEN ? AND ?)
+func WhereRanges(column string, ranges []IntRange, p Placeholder) (string, []any) {
+	if len(ranges) == 0 {
+		return "1 = 0", nil
+	}
+
+	clauses := make([]string, 0, len(ranges))
+	args := make([]any, 0, 2*len(ranges))
+	between := func(min, max int64) {
+		clauses = append(clauses, fmt.Sprintf("%s BETWEEN %s AND %s", column, p(len(args)+1), p(len(args)+2)))
+		args = append(args, min, max)
+	}
+
+	for _, r := range ranges {
+		if r.Min <= math.MaxInt64 && r.Max > math.MaxInt64 {
+			between(int64(r.Min), math.MaxInt64)
+			between(math.MinInt64, int64(r.Max))
+			continue
+		}
+		between(int64(r.Min), int64(r.Max))
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
@@ -0,0 +1,215 @@
+package geohash
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver stub holding a single table in memory.
+// Every Exec appends its arguments as a row and every Query returns all rows.
+type fakeDriver struct {
+	rows [][]driver.Value
+}
+
+type fakeConn struct{ d *fakeDriver }
+type fakeStmt struct{ d *fakeDriver }
+type fakeRows struct {
+	rows [][]driver.Value
+	i    int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeDriver: transactions unsupported")
+}
+func (s fakeStmt) Close() error                              { return nil }
+func (s fakeStmt) NumInput() int                             { return -1 }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) { return &fakeRows{rows: s.d.rows}, nil }
+func (r *fakeRows) Columns() []string                        { return []string{"text", "bigint"} }
+func (r *fakeRows) Close() error                             { return nil }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.rows = append(s.d.rows, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i == len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("geohashfake", fake)
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("geohashfake", "")
+	if err != nil {
+		t.Fatalf("Open = %s", err.Error())
+	}
+	defer db.Close()
+	fake.rows = nil
+
+	for _, c := range testCases {
+		if _, err := db.Exec("INSERT", NewHashInt(c.hashInt>>4, 60), IntHash(NewHashInt(c.hashInt, testBits))); err != nil {
+			t.Fatalf("Exec = %s", err.Error())
+		}
+	}
+
+	// Both columns are stored using driver.Value types: TEXT as string and BIGINT as int64.
+	if _, ok := fake.rows[0][0].(string); !ok {
+		t.Errorf("Value = %T, want string", fake.rows[0][0])
+	}
+	if _, ok := fake.rows[0][1].(int64); !ok {
+		t.Errorf("Value = %T, want int64", fake.rows[0][1])
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("Query = %s", err.Error())
+	}
+	defer rows.Close()
+
+	for _, c := range testCases {
+		if !rows.Next() {
+			t.Fatalf("Next = false, want row for %s", c.hash)
+		}
+
+		h, ih := Hash{}, IntHash{}
+		if err := rows.Scan(&h, &ih); err != nil {
+			t.Fatalf("Scan = %s", err.Error())
+		}
+		if h.String() != c.hash || ih.value != c.hashInt || ih.bits != testBits {
+			t.Errorf("Scan = %s, %x, want %s, %x", h, ih.value, c.hash, c.hashInt)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	h := NewHashInt(0, 20)
+	if err := h.Scan(int64(0xfffff)); err != nil || h.Bits() != 64 || h.Int() != 0xfffff {
+		t.Errorf("Scan = %x, %d bits, %v, want fffff, 64 bits", h.Int(), h.Bits(), err)
+	}
+	if err := h.Scan([]byte("dngb")); err != nil || h.String() != "dngb" {
+		t.Errorf("Scan = %s, %v, want dngb", h, err)
+	}
+	col := HashColumn{Bits: 20}
+	if err := col.Scan(int64(1 << 20)); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Scan = %v, want %v", err, ErrInvalidHash)
+	}
+	if err := h.Scan(1.5); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Scan = %v, want %v", err, ErrInvalidHash)
+	}
+	if err := h.Scan(nil); err != nil || h != (Hash{}) {
+		t.Errorf("Scan = %v, %v, want zero Hash", h, err)
+	}
+}
+
+func TestHashColumnReuse(t *testing.T) {
+	// The same rows scanned in any order give the same hashes, as the previous scan does not change the precision.
+	rows := []any{int64(0xfffff), "dngb", nil, int64(EncodeStrToInt("ddr6"))}
+	want := []string{"zzzz", "dngb", "", "ddr6"}
+
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {1, 3, 2, 0}} {
+		col, h := HashColumn{Bits: 20}, Hash{}
+		for _, i := range order {
+			if err := col.Scan(rows[i]); err != nil || col.Hash.String() != want[i] {
+				t.Errorf("HashColumn.Scan(%v) = %s, %v, want %s", rows[i], col.Hash, err, want[i])
+			}
+			if err := h.Scan(rows[i]); err != nil {
+				t.Errorf("Scan(%v) = %v", rows[i], err)
+			}
+			if _, ok := rows[i].(int64); ok && h.Bits() != bitsMax {
+				t.Errorf("Scan(%v) = %d bits, want %d", rows[i], h.Bits(), bitsMax)
+			}
+		}
+	}
+}
+
+func TestBoxRanges(t *testing.T) {
+	// A box matching a single cell produces a single range of the cell's children.
+	hash := EncodeStrToInt("dngb")
+	res := BoxRanges(DecodeBox("dngb"), 20, testBits)
+	want := []IntRange{{hash << 44, hash<<44 | (1<<44 - 1)}}
+	if !slices.Equal(res, want) {
+		t.Errorf("BoxRanges = %x, want %x", res, want)
+	}
+
+	// The 1024 children of a cell are contiguous and merge to the same range.
+	res = BoxRanges(DecodeBox("dngb"), 30, testBits)
+	if !slices.Equal(res, want) {
+		t.Errorf("BoxRanges = %x, want %x", res, want)
+	}
+
+	for _, c := range testCases {
+		box := DecodeBox(c.hash[:5])
+		box.MaxLat += box.Height()
+		box.MaxLng += box.Width()
+
+		found := false
+		for _, r := range BoxRanges(box, 25, testBits) {
+			found = found || (c.hashInt >= r.Min && c.hashInt <= r.Max)
+		}
+		if !found {
+			t.Errorf("BoxRanges = does not contain %x", c.hashInt)
+		}
+	}
+}
+
+func TestBoxRangesCoarsen(t *testing.T) {
+	// The whole world at 64 bits is covered at a coarser precision, producing one range rather than 2^64 cells.
+	world := Box{MinLat: -latMax, MaxLat: latMax, MinLng: -lngMax, MaxLng: lngMax}
+	res := BoxRanges(world, 64, 64)
+	want := []IntRange{{0, math.MaxUint64}}
+	if !slices.Equal(res, want) {
+		t.Errorf("BoxRanges = %x, want %x", res, want)
+	}
+
+	// Ranges at the coarser precision still contain every point of the box.
+	box := Box{MinLat: 40, MaxLat: 41, MinLng: -75, MaxLng: -73}
+	res = BoxRanges(box, 60, 64)
+	if len(res) > boxRangesMaxCells {
+		t.Errorf("BoxRanges = %d ranges, want at most %d", len(res), boxRangesMaxCells)
+	}
+	for _, p := range [][2]float64{{40, -75}, {40.5, -74}, {40.9999, -73.0001}} {
+		hash := EncodeInt(p[0], p[1])
+		found := false
+		for _, r := range res {
+			found = found || (hash >= r.Min && hash <= r.Max)
+		}
+		if !found {
+			t.Errorf("BoxRanges = does not contain %v", p)
+		}
+	}
+}
+
+func TestWhereRanges(t *testing.T) {
+	ranges := []IntRange{{1, 2}, {math.MaxInt64, math.MaxInt64 + 1}}
+	where, args := WhereRanges("geohash", ranges, PlaceholderDollar)
+
+	want := "(geohash BETWEEN $1 AND $2 OR geohash BETWEEN $3 AND $4 OR geohash BETWEEN $5 AND $6)"
+	if where != want {
+		t.Errorf("WhereRanges = %s, want %s", where, want)
+	}
+	wantArgs := []any{int64(1), int64(2), int64(math.MaxInt64), int64(math.MaxInt64), int64(math.MinInt64), int64(math.MinInt64)}
+	if !slices.Equal(args, wantArgs) {
+		t.Errorf("WhereRanges = %v, want %v", args, wantArgs)
+	}
+
+	if where, _ := WhereRanges("geohash", nil, PlaceholderQuestion); where != "1 = 0" {
+		t.Errorf("WhereRanges = %s, want 1 = 0", where)
+	}
+}
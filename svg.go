@@ -0,0 +1,154 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+const (
+	svgCellsMax = 4096
+	svgWidth    = 800
+)
+
+var (
+	// ErrSVGBox is returned by RenderSVG when the box has no area.
+	ErrSVGBox = errors.New("geohash: box has no area to render")
+
+	// ErrSVGCells is returned by RenderSVG when the box contains more than 4096 cells at the requested precision.
+	ErrSVGCells = errors.New("geohash: too many cells to render")
+)
+
+// SVGOptions configures RenderSVG.
+type SVGOptions struct {
+	// Width is the image width in pixels. The height is derived from the aspect ratio of the box. Defaults to 800.
+	Width int
+
+	// ZOrder draws the Z-order traversal path through the center of each cell in geohash order.
+	ZOrder bool
+
+	// Highlight draws the cell of every prefix of the geohash of Lat, Lng.
+	// Each character narrows the cell to one of 32 sub-cells, illustrating how the encoding converges on the point.
+	Highlight bool
+	Lat, Lng  float64
+}
+
+// RenderSVG draws the geohash cells of the provided character precision that overlap box as an SVG image.
+// Each cell is labeled with its geohash string. Acceptable precision values are 1 to 12 characters.
+// Coordinates are projected as equirectangular (plate carrée), so cells are drawn as they are defined: rectangles in lat, lng.
+// Cells that extend past the box are clipped by the image edges.
+func RenderSVG(w io.Writer, box Box, precision int, opts SVGOptions) error {
+	if box.Width() <= 0 || box.Height() <= 0 {
+		return ErrSVGBox
+	}
+
+	precision = validate(precisionMin, precisionMax, precision)
+	bits := precision * 5
+
+	cells := []uint64{}
+	boxCells(box, bits, func(hash uint64) bool {
+		cells = append(cells, hash)
+		return len(cells) <= svgCellsMax
+	})
+	if len(cells) > svgCellsMax {
+		return ErrSVGCells
+	}
+
+	s := newSVG(w, box, opts.Width)
+	s.printf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\">\n",
+		s.width, s.height, s.width, s.height)
+	s.printf("<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+
+	s.printf("<g fill=\"none\" stroke=\"#7f7f7f\" stroke-width=\"1\">\n")
+	for _, c := range cells {
+		s.rect(decodeBox(EncodeIntToStr(c, precision)), "")
+	}
+	s.printf("</g>\n")
+
+	s.printf("<g fill=\"#333333\" text-anchor=\"middle\" dominant-baseline=\"central\">\n")
+	for _, c := range cells {
+		hash := EncodeIntToStr(c, precision)
+		s.label(decodeBox(hash), hash)
+	}
+	s.printf("</g>\n")
+
+	if opts.ZOrder {
+		// Geohash integers of equal precision sort in Z-order, the order in which interleave visits cells.
+		slices.Sort(cells)
+		s.printf("<polyline fill=\"none\" stroke=\"#d62728\" stroke-width=\"2\" stroke-opacity=\"0.7\" points=\"")
+		for _, c := range cells {
+			lat, lng := decodeBox(EncodeIntToStr(c, precision)).Center()
+			s.printf("%.2f,%.2f ", s.x(lng), s.y(lat))
+		}
+		s.printf("\"/>\n")
+	}
+
+	if opts.Highlight {
+		hash := encode(opts.Lat, opts.Lng, precision)
+		s.printf("<g fill=\"#1f77b4\" fill-opacity=\"0.08\" stroke=\"#1f77b4\" stroke-width=\"2\">\n")
+		for i := 1; i <= len(hash); i++ {
+			b := decodeBox(hash[:i])
+			s.rect(b, fmt.Sprintf("<title>%s</title>", hash[:i]))
+			s.printf("<text x=\"%.2f\" y=\"%.2f\" fill=\"#1f77b4\" fill-opacity=\"1\" stroke=\"none\" font-size=\"12\">%s</text>\n",
+				math.Max(s.x(b.MinLng), 0)+2, math.Max(s.y(b.MaxLat), 0)+12, hash[:i])
+		}
+		s.printf("</g>\n")
+		s.printf("<circle cx=\"%.2f\" cy=\"%.2f\" r=\"4\" fill=\"#1f77b4\"/>\n", s.x(opts.Lng), s.y(opts.Lat))
+	}
+
+	s.printf("</svg>\n")
+	return s.err
+}
+
+// svg holds the projection from a box in degrees to an image in pixels and the first write error encountered.
+type svg struct {
+	w             io.Writer
+	box           Box
+	width, height int
+	err           error
+}
+
+// newSVG returns an svg for box with the provided pixel width, defaulting to 800.
+// The box must have a positive width and height.
+func newSVG(w io.Writer, box Box, width int) *svg {
+	if width <= 0 {
+		width = svgWidth
+	}
+	height := int(math.Round(float64(width) * box.Height() / box.Width()))
+	return &svg{w: w, box: box, width: width, height: max(height, 1)}
+}
+
+// x returns the horizontal pixel position of lng.
+func (s *svg) x(lng float64) float64 {
+	return (lng - s.box.MinLng) / s.box.Width() * float64(s.width)
+}
+
+// y returns the vertical pixel position of lat. Pixel rows increase southward.
+func (s *svg) y(lat float64) float64 {
+	return (s.box.MaxLat - lat) / s.box.Height() * float64(s.height)
+}
+
+// rect draws b as a rectangle with optional child elements.
+func (s *svg) rect(b Box, children string) {
+	s.printf("<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\">%s</rect>\n",
+		s.x(b.MinLng), s.y(b.MaxLat), s.x(b.MaxLng)-s.x(b.MinLng), s.y(b.MinLat)-s.y(b.MaxLat), children)
+}
+
+// label draws text in the center of b, sized to fit within the cell.
+func (s *svg) label(b Box, text string) {
+	lat, lng := b.Center()
+	w := s.x(b.MaxLng) - s.x(b.MinLng)
+	h := s.y(b.MinLat) - s.y(b.MaxLat)
+	size := math.Min(w/(0.65*float64(len(text)+1)), h/2)
+	s.printf("<text x=\"%.2f\" y=\"%.2f\" font-size=\"%.2f\">%s</text>\n", s.x(lng), s.y(lat), size, text)
+}
+
+// printf writes to the underlying io.Writer unless a previous write failed.
+func (s *svg) printf(format string, a ...any) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, format, a...)
+}
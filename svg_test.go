@@ -0,0 +1,62 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+type svgDoc struct {
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+	Groups []struct {
+		Rects []struct{} `xml:"rect"`
+		Texts []string   `xml:"text"`
+	} `xml:"g"`
+	Polylines []struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polyline"`
+}
+
+func TestRenderSVG(t *testing.T) {
+	buf := bytes.Buffer{}
+	opts := SVGOptions{Width: 400, ZOrder: true, Highlight: true, Lat: testLat, Lng: testLng}
+
+	if err := RenderSVG(&buf, DecodeBox("dn"), 3, opts); err != nil {
+		t.Fatalf("RenderSVG = %s", err.Error())
+	}
+
+	doc := svgDoc{}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Unmarshal = %s", err.Error())
+	}
+
+	// Cell "dn" is 11.25 degrees wide and 5.625 degrees tall.
+	if doc.Width != 400 || doc.Height != 200 {
+		t.Errorf("RenderSVG = %dx%d, want 400x200", doc.Width, doc.Height)
+	}
+	if len(doc.Groups) != 3 {
+		t.Fatalf("RenderSVG = %d groups, want 3", len(doc.Groups))
+	}
+	if len(doc.Groups[0].Rects) != 32 || len(doc.Groups[1].Texts) != 32 {
+		t.Errorf("RenderSVG = %d cells with %d labels, want 32", len(doc.Groups[0].Rects), len(doc.Groups[1].Texts))
+	}
+	if res := doc.Groups[2].Texts; len(res) != 3 || res[2] != testHash[:3] {
+		t.Errorf("RenderSVG highlight = %v, want prefixes of %s", res, testHash[:3])
+	}
+	if len(doc.Polylines) != 1 {
+		t.Errorf("RenderSVG = %d polylines, want 1", len(doc.Polylines))
+	}
+}
+
+func TestRenderSVGErrors(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	if err := RenderSVG(&buf, DecodeBox(""), 4, SVGOptions{}); !errors.Is(err, ErrSVGCells) {
+		t.Errorf("RenderSVG = %v, want %v", err, ErrSVGCells)
+	}
+	if err := RenderSVG(&buf, Box{}, 4, SVGOptions{}); !errors.Is(err, ErrSVGBox) {
+		t.Errorf("RenderSVG = %v, want %v", err, ErrSVGBox)
+	}
+}
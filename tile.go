@@ -0,0 +1,134 @@
+package geohash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	tileZoomMax = 32
+	quadkeyBase = "0123"
+)
+
+// ErrInvalidQuadkey is returned by ParseQuadkey for quadkeys with characters other than 0-3 or more than 32 characters.
+var ErrInvalidQuadkey = errors.New("geohash: invalid quadkey")
+
+// Tile is a Web Mercator (EPSG:3857) map tile addressed by zoom level Z and column X, row Y, as used by XYZ (slippy map) tile servers.
+// At zoom Z the world is divided into 2^Z columns from the antimeridian eastward and 2^Z rows from the north.
+// Rows cover latitudes between ±85.05112878, the limit of the Web Mercator projection.
+type Tile struct {
+	X, Y uint32
+	Z    int
+}
+
+// TileFromPoint returns the tile containing the lat, lng coordinates at the provided zoom level.
+// Acceptable zoom values are 0 to 32. Latitudes beyond the Web Mercator limit are placed in the first or last row.
+func TileFromPoint(lat, lng float64, zoom int) Tile {
+	zoom = validate(0, tileZoomMax, zoom)
+	n := math.Exp2(float64(zoom))
+	return Tile{
+		X: uint32(clampTile(math.Floor(tileX(lng)*n), n)),
+		Y: uint32(clampTile(math.Floor(tileY(lat)*n), n)),
+		Z: zoom,
+	}
+}
+
+// Box returns the bounding box of the tile in degrees.
+func (t Tile) Box() Box {
+	n := math.Exp2(float64(t.Z))
+	return Box{
+		MinLat: tileLat(float64(t.Y)+1, n),
+		MaxLat: tileLat(float64(t.Y), n),
+		MinLng: float64(t.X)/n*2*lngMax - lngMax,
+		MaxLng: (float64(t.X)+1)/n*2*lngMax - lngMax,
+	}
+}
+
+// Quadkey returns the Bing Maps quadkey of the tile, a string of Z base-4 digits.
+// Each digit selects a quadrant of the parent tile using a bit from Y (2) and a bit from X (1).
+// The quadkey is the Z-order of the tile's X, Y, so it is produced by interleaving in the same way as a geohash.
+// The X bits take the place of latitude (even bits) and the Y bits take the place of longitude (odd bits).
+func (t Tile) Quadkey() string {
+	hash := interleave(t.X, t.Y)
+
+	qk := make([]byte, t.Z)
+	for i := range qk {
+		qk[i] = quadkeyBase[hash>>(2*(t.Z-1-i))&3]
+	}
+	return string(qk)
+}
+
+// ParseQuadkey returns the tile of a Bing Maps quadkey, reversing Quadkey using deinterleave.
+// ErrInvalidQuadkey is returned for characters other than 0-3 or quadkeys exceeding 32 characters.
+func ParseQuadkey(qk string) (Tile, error) {
+	if len(qk) > tileZoomMax {
+		return Tile{}, fmt.Errorf("%w: %q exceeds %d characters", ErrInvalidQuadkey, qk, tileZoomMax)
+	}
+
+	var hash uint64
+	for i := 0; i < len(qk); i++ {
+		idx := strings.IndexByte(quadkeyBase, qk[i])
+		if idx < 0 {
+			return Tile{}, fmt.Errorf("%w: %q at index %d", ErrInvalidQuadkey, qk[i], i)
+		}
+		hash = hash<<2 | uint64(idx)
+	}
+
+	x, y := deinterleave(hash)
+	return Tile{X: x, Y: y, Z: len(qk)}, nil
+}
+
+// TilesForGeohash returns the tiles at the provided zoom level that overlap the cell of a geohash string.
+// Tiles are ordered row by row starting in the northwest corner.
+// Geohash cells extending beyond the Web Mercator limit overlap the first or last row of tiles.
+func TilesForGeohash(hash string, zoom int) []Tile {
+	zoom = validate(0, tileZoomMax, zoom)
+	box := DecodeBox(hash)
+	n := math.Exp2(float64(zoom))
+
+	// The max lng edge is exclusive, as is the min lat edge, which is the max tile row.
+	x0 := clampTile(math.Floor(tileX(box.MinLng)*n), n)
+	x1 := clampTile(math.Ceil(tileX(box.MaxLng)*n)-1, n)
+	y0 := clampTile(math.Floor(tileY(box.MaxLat)*n), n)
+	y1 := clampTile(math.Ceil(tileY(box.MinLat)*n)-1, n)
+
+	tiles := []Tile{}
+	for y := y0; y <= max(y0, y1); y++ {
+		for x := x0; x <= max(x0, x1); x++ {
+			tiles = append(tiles, Tile{X: uint32(x), Y: uint32(y), Z: zoom})
+		}
+	}
+	return tiles
+}
+
+// GeohashesForTile returns the geohash strings of the provided character precision that overlap the tile.
+// Acceptable precision values are 1 to 12 characters.
+// Cells are ordered row by row starting in the southwest corner.
+func GeohashesForTile(t Tile, precision int) []string {
+	return CoverBox(t.Box(), precision)
+}
+
+// tileX returns the longitude normalized to [0, 1] from west to east.
+func tileX(lng float64) float64 {
+	return (lng + lngMax) / (2 * lngMax)
+}
+
+// tileY returns the Web Mercator projection of latitude normalized to [0, 1] from north to south.
+// Latitudes beyond the Web Mercator limit are clamped to it.
+func tileY(lat float64) float64 {
+	lat = math.Max(-RedisLatMax, math.Min(RedisLatMax, lat))
+	r := lat * math.Pi / 180
+	return (1 - math.Log(math.Tan(r)+1/math.Cos(r))/math.Pi) / 2
+}
+
+// tileLat returns the latitude of the north edge of row y of n rows, the inverse of tileY.
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// clampTile limits a row or column to the n rows or columns of a zoom level.
+func clampTile(v, n float64) float64 {
+	return math.Max(0, math.Min(v, n-1))
+}
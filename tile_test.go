@@ -0,0 +1,134 @@
+package geohash
+
+import (
+	"errors"
+	"testing"
+)
+
+type tileCase struct {
+	lat, lng float64
+	tile     Tile
+	quadkey  string
+}
+
+var tileCases = []tileCase{
+	{51.5074, -0.1278, Tile{X: 511, Y: 340, Z: 10}, "0313131311"},
+	{-33.8688, 151.2093, Tile{X: 14, Y: 9, Z: 4}, "3112"},
+	{0, 0, Tile{X: 0, Y: 0, Z: 0}, ""},
+	{89, -180, Tile{X: 0, Y: 0, Z: 2}, "00"},
+	{-89, 179.9, Tile{X: 3, Y: 3, Z: 2}, "33"},
+}
+
+func TestTileFromPoint(t *testing.T) {
+	for _, c := range tileCases {
+		if res := TileFromPoint(c.lat, c.lng, c.tile.Z); res != c.tile {
+			t.Errorf("TileFromPoint(%f, %f, %d) = %+v, want %+v", c.lat, c.lng, c.tile.Z, res, c.tile)
+		}
+	}
+}
+
+func TestTileBox(t *testing.T) {
+	for _, c := range tileCases {
+		lat := max(-RedisLatMax+1e-9, min(RedisLatMax-1e-9, c.lat))
+		if box := c.tile.Box(); !box.Contains(lat, c.lng) {
+			t.Errorf("Box = %+v, does not contain %f, %f", box, lat, c.lng)
+		}
+	}
+
+	box := Tile{}.Box()
+	if box.MinLng != -180 || box.MaxLng != 180 || box.MaxLat < 85.0511 || box.MinLat > -85.0511 {
+		t.Errorf("Box = %+v, want the Web Mercator world", box)
+	}
+}
+
+func TestQuadkey(t *testing.T) {
+	// Bing Maps Tile System documentation example.
+	if res := (Tile{X: 3, Y: 5, Z: 3}).Quadkey(); res != "213" {
+		t.Errorf("Quadkey = %s, want 213", res)
+	}
+
+	for _, c := range tileCases {
+		if res := c.tile.Quadkey(); res != c.quadkey {
+			t.Errorf("Quadkey = %s, want %s", res, c.quadkey)
+		}
+
+		res, err := ParseQuadkey(c.quadkey)
+		if err != nil {
+			t.Fatalf("ParseQuadkey = %s", err.Error())
+		}
+		if res != c.tile {
+			t.Errorf("ParseQuadkey(%s) = %+v, want %+v", c.quadkey, res, c.tile)
+		}
+	}
+
+	deep := Tile{X: 1<<32 - 1, Y: 1 << 31, Z: 32}
+	if res, _ := ParseQuadkey(deep.Quadkey()); res != deep {
+		t.Errorf("ParseQuadkey = %+v, want %+v", res, deep)
+	}
+
+	for _, qk := range []string{"0124", "a", "000000000000000000000000000000000"} {
+		if _, err := ParseQuadkey(qk); !errors.Is(err, ErrInvalidQuadkey) {
+			t.Errorf("ParseQuadkey(%s) = %v, want %v", qk, err, ErrInvalidQuadkey)
+		}
+	}
+}
+
+func TestTilesForGeohash(t *testing.T) {
+	testCases := []struct {
+		hash string
+		zoom int
+		want []Tile
+	}{
+		// Geohash "u" spans lat 45 to 90, lng 0 to 45, one of the 16 zoom 3 columns and the first rows.
+		{"u", 3, []Tile{{X: 4, Y: 0, Z: 3}, {X: 4, Y: 1, Z: 3}, {X: 4, Y: 2, Z: 3}}},
+		{"gcpvj", 1, []Tile{{X: 0, Y: 0, Z: 1}}},
+		{"gcpvj", 0, []Tile{{}}},
+	}
+
+	for _, c := range testCases {
+		res := TilesForGeohash(c.hash, c.zoom)
+		if len(res) != len(c.want) {
+			t.Fatalf("TilesForGeohash(%s, %d) = %+v, want %+v", c.hash, c.zoom, res, c.want)
+		}
+		for i := range res {
+			if res[i] != c.want[i] {
+				t.Errorf("TilesForGeohash(%s, %d)[%d] = %+v, want %+v", c.hash, c.zoom, i, res[i], c.want[i])
+			}
+		}
+	}
+
+	// Every tile containing a point of the cell must be returned.
+	lat, lng := 51.5074, -0.1278
+	want := TileFromPoint(lat, lng, 14)
+	found := false
+	for _, tile := range TilesForGeohash(EncodePrecision(lat, lng, 5), 14) {
+		found = found || tile == want
+	}
+	if !found {
+		t.Errorf("TilesForGeohash does not contain %+v", want)
+	}
+}
+
+func TestGeohashesForTile(t *testing.T) {
+	tile := TileFromPoint(51.5074, -0.1278, 10)
+	hash := EncodePrecision(51.5074, -0.1278, 5)
+
+	found := false
+	for _, h := range GeohashesForTile(tile, 5) {
+		found = found || h == hash
+	}
+	if !found {
+		t.Errorf("GeohashesForTile(%+v, 5) does not contain %s", tile, hash)
+	}
+
+	if res := GeohashesForTile(Tile{}, 1); len(res) != 32 {
+		t.Errorf("GeohashesForTile = %d cells, want 32", len(res))
+	}
+}
+
+func BenchmarkQuadkey(b *testing.B) {
+	tile := TileFromPoint(51.5074, -0.1278, 23)
+	for n := 0; n < b.N; n++ {
+		tile.Quadkey()
+	}
+}
@@ -0,0 +1,195 @@
+package geohash
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Axis identifies the coordinate bisected by a step of the geohash algorithm.
+type Axis int
+
+// Longitude is bisected first, after which the axes alternate.
+const (
+	AxisLng Axis = iota
+	AxisLat
+)
+
+// String returns "lng" or "lat".
+func (a Axis) String() string {
+	if a == AxisLat {
+		return "lat"
+	}
+	return "lng"
+}
+
+// MarshalText returns the Axis as "lng" or "lat" so traces encode to readable JSON.
+func (a Axis) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses "lng" or "lat" as returned by MarshalText, so traces decode from the JSON they encode to.
+// Any other text returns an error.
+func (a *Axis) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "lng":
+		*a = AxisLng
+	case "lat":
+		*a = AxisLat
+	default:
+		return fmt.Errorf("geohash: invalid axis %q", text)
+	}
+	return nil
+}
+
+// Interval is the range of an axis remaining before or after a bisection step.
+type Interval struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// TraceStep records a single bisection of the encoding or decoding of a geohash.
+// Every 5 steps produce one base32 character of the geohash string.
+type TraceStep struct {
+	// Axis is the coordinate bisected by this step.
+	Axis Axis `json:"axis"`
+
+	// Before and After are the intervals of Axis before and after the bisection.
+	// A bit of 1 keeps the upper half of the interval, while a bit of 0 keeps the lower half.
+	Before Interval `json:"before"`
+	After  Interval `json:"after"`
+	Bit    uint8    `json:"bit"`
+
+	// Index is the position of the character this step contributes to in the geohash string.
+	Index int `json:"index"`
+
+	// Group is the value of the 5-bit character group including this step's bit, aligned as though the remaining bits are 0.
+	Group uint8 `json:"group"`
+
+	// Char is the base32 character of Group once its fifth bit is set, otherwise it is empty.
+	Char string `json:"char,omitempty"`
+}
+
+// Trace is a step by step log of encoding or decoding a geohash string.
+// Lat, Lng are the encoded coordinates or, when decoding, the center of the final cell.
+type Trace struct {
+	Hash  string      `json:"hash"`
+	Lat   float64     `json:"lat"`
+	Lng   float64     `json:"lng"`
+	Box   Box         `json:"box"`
+	Steps []TraceStep `json:"steps"`
+}
+
+// EncodeTrace returns the geohash string of the lat, lng coordinates along with every bisection step taken to produce it.
+// Acceptable precision values are 1 to 20 characters.
+// The bisection is identical to encodeBitwiseOr, so the resulting hash matches EncodeHighPrecision.
+func EncodeTrace(lat, lng float64, precision int) Trace {
+	precision = validate(precisionMin, precisionHigh, precision)
+
+	t := newTrace(precision)
+	t.Lat, t.Lng = lat, lng
+
+	hash := make([]byte, 0, precision)
+	for i := 0; i < precision*5; i++ {
+		x := lng
+		if t.axis(i) == AxisLat {
+			x = lat
+		}
+
+		iv := t.interval(i)
+		var bit uint8
+		if x >= (iv.Min+iv.Max)/2 {
+			bit = 1
+		}
+
+		if step := t.step(i, bit); step.Char != "" {
+			hash = append(hash, step.Char[0])
+		}
+	}
+	t.Hash = string(hash)
+	t.Box = t.box()
+
+	return t
+}
+
+// DecodeTrace returns the center and box of a geohash string along with every bisection step taken to decode it.
+// The geohash string is truncated to the precision max of 20 characters.
+// Unlike Decode, characters outside the geohash base32 alphabet result in ErrInvalidHash.
+func DecodeTrace(hash string) (Trace, error) {
+	hash = truncateHigh(hash)
+
+	t := newTrace(len(hash))
+	t.Hash = hash
+
+	for i := 0; i < len(hash)*5; i++ {
+		idx := bytes.IndexByte([]byte(base32), hash[i/5])
+		if idx < 0 {
+			return Trace{}, fmt.Errorf("%w: %q at index %d", ErrInvalidHash, hash[i/5], i/5)
+		}
+		t.step(i, uint8(idx>>(4-i%5)&1))
+	}
+	t.Box = t.box()
+	t.Lat, t.Lng = t.Box.Center()
+
+	return t, nil
+}
+
+// newTrace returns a Trace with capacity for precision characters of steps.
+func newTrace(precision int) Trace {
+	return Trace{Steps: make([]TraceStep, 0, precision*5)}
+}
+
+// axis returns the axis bisected by step i.
+func (t *Trace) axis(i int) Axis {
+	return Axis(i % 2)
+}
+
+// interval returns the interval of the axis of step i prior to bisection.
+// This is the After interval of the previous step on the same axis, or the full range of the axis.
+func (t *Trace) interval(i int) Interval {
+	if i >= 2 {
+		return t.Steps[i-2].After
+	}
+	if t.axis(i) == AxisLat {
+		return Interval{-latMax, latMax}
+	}
+	return Interval{-lngMax, lngMax}
+}
+
+// step appends step i using bit to bisect the interval and returns the appended step.
+func (t *Trace) step(i int, bit uint8) TraceStep {
+	before := t.interval(i)
+	after := before
+	mid := (before.Min + before.Max) / 2
+	if bit == 1 {
+		after.Min = mid
+	} else {
+		after.Max = mid
+	}
+
+	var group uint8
+	if i%5 != 0 {
+		group = t.Steps[i-1].Group
+	}
+	group |= bit << (4 - i%5)
+
+	s := TraceStep{Axis: t.axis(i), Before: before, After: after, Bit: bit, Index: i / 5, Group: group}
+	if i%5 == 4 {
+		s.Char = string(base32[group])
+	}
+	t.Steps = append(t.Steps, s)
+
+	return s
+}
+
+// box returns the cell formed by the final lat and lng intervals of the trace.
+func (t *Trace) box() Box {
+	b := Box{MinLat: -latMax, MaxLat: latMax, MinLng: -lngMax, MaxLng: lngMax}
+	for _, s := range t.Steps {
+		if s.Axis == AxisLat {
+			b.MinLat, b.MaxLat = s.After.Min, s.After.Max
+		} else {
+			b.MinLng, b.MaxLng = s.After.Min, s.After.Max
+		}
+	}
+	return b
+}
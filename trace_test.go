@@ -0,0 +1,130 @@
+package geohash
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTrace(t *testing.T) {
+	for _, c := range testCases {
+		res := EncodeTrace(c.lat, c.lng, testPrecisionHigh)
+
+		if res.Hash != c.hashHighPrec {
+			t.Errorf("EncodeTrace = %s, want %s", res.Hash, c.hashHighPrec)
+		}
+		if len(res.Steps) != testPrecisionHigh*5 {
+			t.Errorf("EncodeTrace = %d steps, want %d", len(res.Steps), testPrecisionHigh*5)
+		}
+		if !res.Box.Contains(c.lat, c.lng) {
+			t.Errorf("EncodeTrace = %+v, does not contain %.9f, %.9f", res.Box, c.lat, c.lng)
+		}
+
+		chars := strings.Builder{}
+		for _, s := range res.Steps {
+			chars.WriteString(s.Char)
+		}
+		if chars.String() != c.hashHighPrec {
+			t.Errorf("EncodeTrace chars = %s, want %s", chars.String(), c.hashHighPrec)
+		}
+	}
+}
+
+func TestEncodeTraceSteps(t *testing.T) {
+	// The first character of testHash is "d" (01100).
+	res := EncodeTrace(testLat, testLng, 1)
+	want := []TraceStep{
+		{AxisLng, Interval{-180, 180}, Interval{-180, 0}, 0, 0, 0, ""},
+		{AxisLat, Interval{-90, 90}, Interval{0, 90}, 1, 0, 8, ""},
+		{AxisLng, Interval{-180, 0}, Interval{-90, 0}, 1, 0, 12, ""},
+		{AxisLat, Interval{0, 90}, Interval{0, 45}, 0, 0, 12, ""},
+		{AxisLng, Interval{-90, 0}, Interval{-90, -45}, 0, 0, 12, "d"},
+	}
+
+	if len(res.Steps) != len(want) {
+		t.Fatalf("EncodeTrace = %d steps, want %d", len(res.Steps), len(want))
+	}
+	for i, w := range want {
+		if res.Steps[i] != w {
+			t.Errorf("EncodeTrace step %d = %+v, want %+v", i, res.Steps[i], w)
+		}
+	}
+}
+
+func TestDecodeTrace(t *testing.T) {
+	for _, c := range testCases {
+		res, err := DecodeTrace(c.hashHighPrec)
+		if err != nil {
+			t.Fatalf("DecodeTrace = %s", err.Error())
+		}
+
+		lat, lng := DecodeHighPrecision(c.hashHighPrec)
+		if res.Lat != lat || res.Lng != lng {
+			t.Errorf("DecodeTrace = %.9f, %.9f, want %.9f, %.9f", res.Lat, res.Lng, lat, lng)
+		}
+
+		enc := EncodeTrace(c.lat, c.lng, testPrecisionHigh)
+		for i := range res.Steps {
+			if res.Steps[i] != enc.Steps[i] {
+				t.Errorf("DecodeTrace step %d = %+v, want %+v", i, res.Steps[i], enc.Steps[i])
+			}
+		}
+	}
+
+	if _, err := DecodeTrace("dnga"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("DecodeTrace = %v, want %v", err, ErrInvalidHash)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	b, err := json.Marshal(EncodeTrace(testLat, testLng, 1))
+	if err != nil {
+		t.Fatalf("Marshal = %s", err.Error())
+	}
+
+	want := `{"axis":"lng","before":{"min":-180,"max":180},"after":{"min":-180,"max":0},"bit":0,"index":0,"group":0}`
+	if !strings.Contains(string(b), want) {
+		t.Errorf("Marshal = %s, want to contain %s", b, want)
+	}
+}
+
+func TestTraceJSONRoundTrip(t *testing.T) {
+	for _, want := range []Trace{EncodeTrace(testLat, testLng, testPrecision), mustDecodeTrace(t, testHash)} {
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("Marshal = %s", err.Error())
+		}
+		var res Trace
+		if err := json.Unmarshal(b, &res); err != nil {
+			t.Fatalf("Unmarshal = %s", err.Error())
+		}
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("Unmarshal = %+v, want %+v", res, want)
+		}
+	}
+
+	var a Axis
+	for _, text := range []string{"", "LAT", "0", "x"} {
+		if err := a.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = nil, want error", text)
+		}
+	}
+}
+
+// mustDecodeTrace returns DecodeTrace of hash, failing the test on error.
+func mustDecodeTrace(t *testing.T, hash string) Trace {
+	t.Helper()
+	res, err := DecodeTrace(hash)
+	if err != nil {
+		t.Fatalf("DecodeTrace = %s", err.Error())
+	}
+	return res
+}
+
+func BenchmarkEncodeTrace(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		EncodeTrace(testLat, testLng, testPrecision)
+	}
+}
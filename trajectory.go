@@ -0,0 +1,112 @@
+package geohash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidTrajectory is returned by DecodeTrajectory for data that is truncated or was not produced by EncodeTrajectory.
+var ErrInvalidTrajectory = errors.New("geohash: invalid trajectory")
+
+// TrajectoryPoint is a timestamped position of a trajectory, such as a GPS fix.
+type TrajectoryPoint struct {
+	Time time.Time `json:"time"`
+	Lat  float64   `json:"lat"`
+	Lng  float64   `json:"lng"`
+}
+
+// EncodeTrajectory returns a compact encoding of a sequence of points quantized to geohash cells of the provided bit precision.
+// Acceptable bit values are 1 to 64. Each point is encoded using EncodeIntPrecision and deinterleaved into its row and column.
+// Consecutive points of a trajectory are close in time and space, so the differences between their rows, columns and times are small.
+// Each point is stored as the differences from the previous point,
+// using the zigzag varint encoding of binary.AppendVarint, where small positive and negative values take a single byte.
+// Column differences wrap across the antimeridian, so crossing it does not produce a large difference.
+// Times are stored at millisecond resolution.
+//
+// The format is: uvarint bits, uvarint number of points, then for each point varint time, varint row and varint column.
+func EncodeTrajectory(points []TrajectoryPoint, bits int) []byte {
+	bits = validate(bitsMin, bitsMax, bits)
+
+	data := binary.AppendUvarint(nil, uint64(bits))
+	data = binary.AppendUvarint(data, uint64(len(points)))
+
+	// The first point is the difference from the cell at row 0, column 0 and time 0.
+	var prevTime int64
+	var prevHash uint64
+	for _, p := range points {
+		hash := encodeIntClamp(p.Lat, p.Lng, bits)
+		t := p.Time.UnixMilli()
+		dLat, dLng := gridOffset(prevHash, hash, bits)
+
+		data = binary.AppendVarint(data, t-prevTime)
+		data = binary.AppendVarint(data, dLat)
+		data = binary.AppendVarint(data, dLng)
+		prevTime, prevHash = t, hash
+	}
+
+	return data
+}
+
+// DecodeTrajectory returns the points of a trajectory encoded by EncodeTrajectory.
+// Each point is the center of its cell, so its error is bounded by TrajectoryError of the encoded bit precision.
+// Times are returned in UTC. ErrInvalidTrajectory is returned for truncated or malformed data.
+func DecodeTrajectory(data []byte) ([]TrajectoryPoint, error) {
+	bits, n := binary.Uvarint(data)
+	if n <= 0 || bits < bitsMin || bits > bitsMax {
+		return nil, fmt.Errorf("%w: invalid bit precision", ErrInvalidTrajectory)
+	}
+	data = data[n:]
+
+	// Each point takes at least 3 bytes, which bounds the allocation for corrupt counts.
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)-n)/3 {
+		return nil, fmt.Errorf("%w: invalid number of points", ErrInvalidTrajectory)
+	}
+	data = data[n:]
+
+	latBits, lngBits := gridBits(int(bits))
+	rows, cols := int64(1)<<latBits, int64(1)<<lngBits
+	latStep := 2 * latMax / float64(rows)
+	lngStep := 2 * lngMax / float64(cols)
+
+	points := make([]TrajectoryPoint, count)
+	var t, lat, lng int64
+	for i := range points {
+		var d [3]int64
+		for j := range d {
+			if d[j], n = binary.Varint(data); n <= 0 {
+				return nil, fmt.Errorf("%w: truncated at point %d", ErrInvalidTrajectory, i)
+			}
+			data = data[n:]
+		}
+
+		t += d[0]
+		lat += d[1]
+		lng = ((lng+d[2])%cols + cols) % cols
+		if lat < 0 || lat >= rows {
+			return nil, fmt.Errorf("%w: row out of range at point %d", ErrInvalidTrajectory, i)
+		}
+
+		points[i] = TrajectoryPoint{
+			Time: time.UnixMilli(t).UTC(),
+			Lat:  -latMax + (float64(lat)+0.5)*latStep,
+			Lng:  -lngMax + (float64(lng)+0.5)*lngStep,
+		}
+	}
+
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidTrajectory, len(data))
+	}
+	return points, nil
+}
+
+// TrajectoryError returns the maximum lat, lng error in degrees of points decoded by DecodeTrajectory for a bit precision.
+// This is half the height and width of a cell, as points are decoded to the center of their cell.
+func TrajectoryError(bits int) (float64, float64) {
+	bits = validate(bitsMin, bitsMax, bits)
+	latBits, lngBits := gridBits(bits)
+	return latMax / math.Exp2(float64(latBits)), lngMax / math.Exp2(float64(lngBits))
+}
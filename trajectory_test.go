@@ -0,0 +1,132 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// syntheticTrace returns a trajectory of n GPS fixes one second apart, following a vehicle that drives at 5 to 20 m/s,
+// turns gradually, and stops occasionally, with 3m of GPS noise on each fix.
+func syntheticTrace(n int, seed int64) []TrajectoryPoint {
+	r := rand.New(rand.NewSource(seed))
+	points := make([]TrajectoryPoint, n)
+
+	t := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	lat, lng := 40.7128, -74.0060
+	heading, speed := r.Float64()*2*math.Pi, 10.0
+
+	for i := range points {
+		heading += r.NormFloat64() * 0.1
+		speed = math.Max(0, math.Min(20, speed+r.NormFloat64()))
+		if r.Intn(100) == 0 {
+			speed = 0
+		}
+
+		lat += speed * math.Cos(heading) / metersPerDegree
+		lng += speed * math.Sin(heading) / (metersPerDegree * math.Cos(lat*math.Pi/180))
+		points[i] = TrajectoryPoint{
+			Time: t.Add(time.Duration(i) * time.Second),
+			Lat:  lat + r.NormFloat64()*3/metersPerDegree,
+			Lng:  lng + r.NormFloat64()*3/metersPerDegree,
+		}
+	}
+	return points
+}
+
+func TestTrajectory(t *testing.T) {
+	trace := syntheticTrace(1000, 1)
+	trace = append(trace,
+		// Crossing the antimeridian and a gap in time.
+		TrajectoryPoint{Time: time.UnixMilli(1714560000000).UTC(), Lat: -16.5, Lng: 179.999},
+		TrajectoryPoint{Time: time.UnixMilli(1714560001000).UTC(), Lat: -16.5, Lng: -179.999},
+	)
+
+	for _, bits := range []int{1, 20, 40, 52, 64} {
+		data := EncodeTrajectory(trace, bits)
+		res, err := DecodeTrajectory(data)
+		if err != nil {
+			t.Fatalf("DecodeTrajectory = %s", err.Error())
+		}
+		if len(res) != len(trace) {
+			t.Fatalf("DecodeTrajectory = %d points, want %d", len(res), len(trace))
+		}
+
+		latErr, lngErr := TrajectoryError(bits)
+		for i := range res {
+			if !res[i].Time.Equal(trace[i].Time.Truncate(time.Millisecond)) {
+				t.Errorf("DecodeTrajectory time = %v, want %v", res[i].Time, trace[i].Time)
+			}
+			if math.Abs(res[i].Lat-trace[i].Lat) > latErr || math.Abs(res[i].Lng-trace[i].Lng) > lngErr {
+				t.Errorf("DecodeTrajectory(%d) = %f, %f, want within %g, %g of %f, %f", bits, res[i].Lat, res[i].Lng, latErr, lngErr, trace[i].Lat, trace[i].Lng)
+			}
+		}
+	}
+
+	// Small differences take a byte each, so a dense trace is far smaller than the 24 bytes of a time and two float64 values.
+	if data := EncodeTrajectory(trace[:1000], 40); len(data) > 6*1000 {
+		t.Errorf("EncodeTrajectory = %d bytes for 1000 points, want at most 6000", len(data))
+	}
+
+	if res, err := DecodeTrajectory(EncodeTrajectory(nil, 40)); err != nil || len(res) != 0 {
+		t.Errorf("DecodeTrajectory = %v, %v, want no points", res, err)
+	}
+}
+
+func TestTrajectoryMaxEdge(t *testing.T) {
+	trace := []TrajectoryPoint{
+		{Time: time.UnixMilli(1714560000000).UTC(), Lat: 90, Lng: 180},
+		{Time: time.UnixMilli(1714560001000).UTC(), Lat: 89.99, Lng: 179.99},
+	}
+
+	res, err := DecodeTrajectory(EncodeTrajectory(trace, 40))
+	if err != nil {
+		t.Fatalf("DecodeTrajectory = %s", err.Error())
+	}
+	latErr, lngErr := TrajectoryError(40)
+	for i := range res {
+		if math.Abs(res[i].Lat-trace[i].Lat) > latErr || math.Abs(res[i].Lng-trace[i].Lng) > lngErr {
+			t.Errorf("DecodeTrajectory = %f, %f, want within %g, %g of %f, %f", res[i].Lat, res[i].Lng, latErr, lngErr, trace[i].Lat, trace[i].Lng)
+		}
+	}
+}
+
+func TestDecodeTrajectoryInvalid(t *testing.T) {
+	data := EncodeTrajectory(syntheticTrace(10, 2), 40)
+
+	for _, d := range [][]byte{nil, {0}, {65}, {40, 0xff, 0xff, 0xff, 0x0f}, data[:len(data)-1], append(data, 0)} {
+		if _, err := DecodeTrajectory(d); !errors.Is(err, ErrInvalidTrajectory) {
+			t.Errorf("DecodeTrajectory(%x) = %v, want %v", d, err, ErrInvalidTrajectory)
+		}
+	}
+
+	// A row beyond the grid is rejected rather than decoded to an invalid latitude.
+	if _, err := DecodeTrajectory([]byte{2, 1, 0, 4, 0}); !errors.Is(err, ErrInvalidTrajectory) {
+		t.Errorf("DecodeTrajectory = %v, want %v", err, ErrInvalidTrajectory)
+	}
+}
+
+// The trajectory benchmarks report the compression ratio against 24 bytes per point (int64 time and two float64 values).
+func BenchmarkEncodeTrajectory(b *testing.B) {
+	for _, bits := range []int{32, 40, 52} {
+		b.Run(strconv.Itoa(bits), func(b *testing.B) {
+			trace := syntheticTrace(10000, 1)
+			var data []byte
+			for n := 0; n < b.N; n++ {
+				data = EncodeTrajectory(trace, bits)
+			}
+			b.ReportMetric(float64(24*len(trace))/float64(len(data)), "ratio")
+			b.ReportMetric(float64(len(data))/float64(len(trace)), "bytes/point")
+		})
+	}
+}
+
+func BenchmarkDecodeTrajectory(b *testing.B) {
+	data := EncodeTrajectory(syntheticTrace(10000, 1), 40)
+	for n := 0; n < b.N; n++ {
+		DecodeTrajectory(data)
+	}
+}